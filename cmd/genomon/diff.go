@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// diffOptions holds the flags accepted by the diff command.
+type diffOptions struct {
+	enrichedFile string
+	asJSON       bool
+}
+
+// reviewItem is a parsed effect whose source text changed between snapshots.
+type reviewItem struct {
+	CardID string      `json:"cardId"`
+	Card   string      `json:"card"`
+	Field  string      `json:"field"`
+	Effect core.Effect `json:"effect"`
+}
+
+func handleDiffCommand(opts diffOptions, args []string) {
+	if len(args) != 2 {
+		fmt.Println("Usage: genomon diff [options] <old.json> <new.json>")
+		os.Exit(1)
+	}
+
+	oldCards, err := loadRawCards(args[0])
+	if err != nil {
+		fmt.Printf("Error reading %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	newCards, err := loadRawCards(args[1])
+	if err != nil {
+		fmt.Printf("Error reading %s: %v\n", args[1], err)
+		os.Exit(1)
+	}
+
+	changelog := tcgdex.Diff(oldCards, newCards)
+
+	// Flag parsed effects built from text that has since changed, if we have them.
+	var review []reviewItem
+	if opts.enrichedFile != "" {
+		enriched, err := loadEnrichedCards(opts.enrichedFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cannot read %s, skipping re-review check: %v\n", opts.enrichedFile, err)
+		} else {
+			review = effectsNeedingReview(changelog, enriched)
+		}
+	}
+
+	if opts.asJSON {
+		output := struct {
+			*tcgdex.Changelog
+			NeedsReview []reviewItem `json:"needsReview,omitempty"`
+		}{changelog, review}
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			fmt.Printf("Error marshalling changelog to JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	printChangelog(changelog, review)
+}
+
+// effectsNeedingReview finds the parsed effects of enriched cards whose
+// attack, ability or trainer text was changed in the changelog.
+func effectsNeedingReview(changelog *tcgdex.Changelog, enriched []core.Card) []reviewItem {
+	byID := make(map[string]core.Card, len(enriched))
+	for _, card := range enriched {
+		byID[card.ID] = card
+	}
+
+	var review []reviewItem
+	for _, change := range changelog.TextChanges() {
+		card, ok := byID[change.ID]
+		if !ok {
+			continue
+		}
+		for _, field := range change.Changes {
+			if !field.EffectText {
+				continue
+			}
+			if field.Source == "" {
+				// Trainer text belongs to the whole card.
+				for _, effect := range card.ParsedTrainerEffects {
+					review = append(review, reviewItem{CardID: card.ID, Card: card.Name, Field: field.Field, Effect: effect})
+				}
+				continue
+			}
+
+			// Effects are named after their attack or ability, which may
+			// have been renamed, so also match the one at the same position.
+			names := []string{field.Source}
+			var parsed []core.Effect
+			var i int
+			switch {
+			case fieldIndex(field.Field, "attacks", &i):
+				parsed = card.ParsedAttacks
+				if i < len(card.Attacks) {
+					names = append(names, card.Attacks[i].Name)
+				}
+			case fieldIndex(field.Field, "abilities", &i):
+				parsed = card.ParsedAbilities
+				if i < len(card.Abilities) {
+					names = append(names, card.Abilities[i].Name)
+				}
+			}
+			for _, effect := range parsed {
+				if slices.Contains(names, effect.Name) {
+					review = append(review, reviewItem{CardID: card.ID, Card: card.Name, Field: field.Field, Effect: effect})
+				}
+			}
+		}
+	}
+	return review
+}
+
+// fieldIndex reads the index from a changed field's path, such as 1 from
+// "attacks[1].effect", if the path is in the list called name.
+func fieldIndex(field, name string, index *int) bool {
+	_, err := fmt.Sscanf(field, name+"[%d]", index)
+	return err == nil
+}
+
+func printChangelog(changelog *tcgdex.Changelog, review []reviewItem) {
+	if changelog.Empty() {
+		fmt.Println("No changes.")
+		return
+	}
+
+	for _, set := range changelog.AddedSets {
+		fmt.Printf("Set added: %s (%s)\n", set.ID, set.Name)
+	}
+	for _, set := range changelog.RemovedSets {
+		fmt.Printf("Set removed: %s (%s)\n", set.ID, set.Name)
+	}
+
+	if len(changelog.AddedCards) > 0 {
+		fmt.Printf("\nNew cards (%d):\n", len(changelog.AddedCards))
+		for _, card := range changelog.AddedCards {
+			fmt.Printf("  + %s %s\n", card.ID, card.Name)
+		}
+	}
+	if len(changelog.RemovedCards) > 0 {
+		fmt.Printf("\nRemoved cards (%d):\n", len(changelog.RemovedCards))
+		for _, card := range changelog.RemovedCards {
+			fmt.Printf("  - %s %s\n", card.ID, card.Name)
+		}
+	}
+	if len(changelog.ChangedCards) > 0 {
+		fmt.Printf("\nChanged cards (%d):\n", len(changelog.ChangedCards))
+		for _, change := range changelog.ChangedCards {
+			fmt.Printf("  ~ %s %s\n", change.ID, change.Name)
+			for _, field := range change.Changes {
+				marker := ""
+				if field.EffectText {
+					marker = " [errata]"
+				}
+				fmt.Printf("      %s%s: %q → %q\n", field.Field, marker, field.Old, field.New)
+			}
+		}
+	}
+
+	if len(review) > 0 {
+		fmt.Printf("\n⚠️  %d parsed effect(s) need re-review:\n", len(review))
+		for _, item := range review {
+			fmt.Printf("  └─ %s %s, %s: %s '%s'\n", item.CardID, item.Card, item.Field, item.Effect.Type, item.Effect.Name)
+		}
+	}
+}
+
+// loadEnrichedCards reads a card file previously written by the process command.
+func loadEnrichedCards(path string) ([]core.Card, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []core.Card
+	if err := json.Unmarshal(data, &cards); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+func TestEffectsNeedingReviewTrainerText(t *testing.T) {
+	potion := tcgdex.Card{ID: "P-A-001", Name: "Potion", Category: "Trainer", TrainerType: "Item",
+		Text: "Heal 20 damage from 1 of your Pokémon."}
+	errata := potion
+	errata.Text = "Heal 30 damage from 1 of your Pokémon."
+
+	enriched := []core.Card{{
+		Card:                 potion,
+		ParsedTrainerEffects: []core.Effect{{Name: "Potion", Type: core.EffectHeal, Amount: 20, Description: potion.Text}},
+	}}
+	review := effectsNeedingReview(tcgdex.Diff([]tcgdex.Card{potion}, []tcgdex.Card{errata}), enriched)
+	if len(review) != 1 || review[0].Field != "effect" || review[0].Effect.Type != core.EffectHeal {
+		t.Errorf("effectsNeedingReview = %+v, want the Potion's HEAL effect", review)
+	}
+}
+
+func TestEffectsNeedingReviewRenamedAttack(t *testing.T) {
+	text := "Discard 2 {R} Energy from this Pokémon. This attack does 80 damage to 1 of your opponent's Pokémon."
+	old := tcgdex.Card{ID: "A1-036", Name: "Charizard", Category: "Pokemon", Attacks: []tcgdex.Attack{
+		{Name: "Slash"},
+		{Name: "Fire Spin", Effect: text},
+	}}
+	renamed := old
+	renamed.Attacks = []tcgdex.Attack{old.Attacks[0], {Name: "Inferno Spin", Effect: "Discard 3 {R} Energy from this Pokémon. This attack does 80 damage to 1 of your opponent's Pokémon."}}
+
+	// Each effect describes only its own clause, not the attack's whole text.
+	enriched := []core.Card{{Card: old, ParsedAttacks: []core.Effect{
+		{Name: "Slash", Type: core.EffectHeal},
+		{Name: "Fire Spin", Type: core.EffectDiscardEnergy, Description: "Discard 2 {R} Energy from this Pokémon."},
+		{Name: "Fire Spin", Type: core.EffectDamage, Description: "This attack does 80 damage to 1 of your opponent's Pokémon."},
+	}}}
+	review := effectsNeedingReview(tcgdex.Diff([]tcgdex.Card{old}, []tcgdex.Card{renamed}), enriched)
+	if len(review) != 2 || review[0].Effect.Type != core.EffectDiscardEnergy || review[1].Effect.Type != core.EffectDamage {
+		t.Errorf("effectsNeedingReview = %+v, want both Fire Spin effects", review)
+	}
+}
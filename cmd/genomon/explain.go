@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/internal/effects"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// explainOptions holds the flags accepted by the explain command.
+type explainOptions struct {
+	inputFile string
+	rulesFile string
+	asJSON    bool
+}
+
+// cardExplanation is the explain command's JSON output for one card.
+type cardExplanation struct {
+	Card     string                `json:"card"`
+	CardName string                `json:"cardName"`
+	Version  string                `json:"version"`
+	Texts    []effects.Explanation `json:"texts"`
+}
+
+// handleExplainCommand parses the effect texts of the cards with the given
+// IDs and shows, for each attack, ability and Trainer card text, which rules
+// produced its effects and from what text.
+func handleExplainCommand(opts explainOptions, ids []string) {
+	if len(ids) == 0 {
+		fmt.Println("Error: explain needs at least one card ID, e.g. genomon explain A1-001")
+		os.Exit(1)
+	}
+	if opts.rulesFile != "" {
+		rules, err := effects.LoadRulesFile(opts.rulesFile)
+		if err != nil {
+			fmt.Printf("Error loading effect rules: %v\n", err)
+			os.Exit(1)
+		}
+		effects.SetRules(rules)
+	}
+
+	data, err := os.ReadFile(opts.inputFile)
+	if err != nil {
+		fmt.Printf("Error reading input file: %v\n", err)
+		os.Exit(1)
+	}
+	var cards []tcgdex.Card
+	if err := json.Unmarshal(data, &cards); err != nil {
+		fmt.Printf("Error unmarshalling card data: %v\n", err)
+		os.Exit(1)
+	}
+	byID := make(map[string]tcgdex.Card, len(cards))
+	for _, card := range cards {
+		byID[card.ID] = card
+	}
+
+	var explained []cardExplanation
+	for _, id := range ids {
+		card, ok := byID[id]
+		if !ok {
+			fmt.Printf("Error: no card with ID %q in %s\n", id, opts.inputFile)
+			os.Exit(1)
+		}
+		explained = append(explained, cardExplanation{
+			Card:     card.ID,
+			CardName: card.Name,
+			Version:  effects.Version(),
+			Texts:    effects.Explain(card),
+		})
+	}
+
+	if opts.asJSON {
+		data, err := json.MarshalIndent(explained, "", "  ")
+		if err != nil {
+			fmt.Printf("Error marshalling explanation: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	for i, card := range explained {
+		if i > 0 {
+			fmt.Println()
+		}
+		printCardExplanation(card)
+	}
+}
+
+func printCardExplanation(card cardExplanation) {
+	fmt.Printf("%s (%s), parsed by %s\n", card.CardName, card.Card, card.Version)
+	if len(card.Texts) == 0 {
+		fmt.Println("  This card has no effect text.")
+	}
+	for _, text := range card.Texts {
+		if text.Name != "" {
+			fmt.Printf("\n%s %q: %s\n", capitalizeKind(text.Kind), text.Name, text.Text)
+		} else {
+			fmt.Printf("\n%s: %s\n", capitalizeKind(text.Kind), text.Text)
+		}
+		for _, effect := range text.Effects {
+			printEffectProvenance(effect)
+		}
+		for _, clause := range text.Unparsed {
+			fmt.Printf("  ❌ Not parsed: %q\n", clause)
+		}
+		for _, clause := range text.Clauses {
+			switch {
+			case clause.Scope == effects.ScopeTrainer && len(clause.Rules) == 0:
+				fmt.Printf("  Trainer text %q matches no trainer rule.\n", clause.Text)
+				continue
+			case clause.Scope == effects.ScopeTrainer:
+				fmt.Printf("  Trainer text %q matches the trainer rules:\n", clause.Text)
+			case len(clause.Rules) == 0:
+				fmt.Printf("  Clause %q matches no rule on its own.\n", clause.Text)
+				continue
+			default:
+				fmt.Printf("  Clause %q on its own matches:\n", clause.Text)
+			}
+			for _, rule := range clause.Rules {
+				fmt.Printf("       - %s\n", rule)
+			}
+		}
+	}
+}
+
+func printEffectProvenance(effect core.Effect) {
+	provenance := effect.Provenance
+	if effect.Type == core.EffectUnknown || provenance == nil {
+		fmt.Printf("  ❌ %s: no rule matched %q\n", effect.Type, effect.Description)
+		return
+	}
+	fmt.Printf("  ✅ %s from rule %q\n", effect.Type, provenance.Rule)
+	if len(provenance.Span) == 2 {
+		fmt.Printf("  └─ matched %q at bytes %d-%d\n", provenance.Text, provenance.Span[0], provenance.Span[1])
+	} else {
+		fmt.Printf("  └─ matched %q\n", provenance.Text)
+	}
+	if len(provenance.Captures) > 0 {
+		fmt.Printf("  └─ captured %q\n", provenance.Captures)
+	}
+}
+
+// capitalizeKind turns an Explanation's kind into a heading, such as
+// "Attack".
+func capitalizeKind(kind string) string {
+	if kind == "" {
+		return kind
+	}
+	return strings.ToUpper(kind[:1]) + kind[1:]
+}
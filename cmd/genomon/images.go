@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cpritch/genomon/internal/imagestore"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// imageResult is the outcome of syncing card images.
+type imageResult struct {
+	Downloaded int                // Images that were new or changed
+	Unchanged  int                // Images revalidated without re-downloading
+	NoImage    int                // Cards TCGdex has no artwork for
+	Failed     []tcgdex.CardError // Images that failed even after retrying
+}
+
+// syncImages downloads the artwork of cards into store, using at most
+// concurrency requests at a time. Images already in the store are revalidated
+// with a conditional request and only re-downloaded if they changed. Progress
+// is reported to report, if not nil. The manifest is not saved; that is left
+// to the caller.
+func syncImages(ctx context.Context, client *tcgdex.Client, store *imagestore.Store, cards []tcgdex.Card, quality tcgdex.ImageQuality, format tcgdex.ImageFormat, concurrency int, report tcgdex.ProgressFunc) *imageResult {
+	result := &imageResult{}
+	var mu sync.Mutex // Guards result and serialises progress reports
+	done := 0
+
+	jobs := make(chan *tcgdex.Card)
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for card := range jobs {
+				changed, err := syncImage(ctx, client, store, card, quality, format)
+
+				mu.Lock()
+				done++
+				event := tcgdex.ProgressEvent{Kind: tcgdex.ProgressCardFetched, SetID: "images", CardID: card.ID, CardName: card.Name, Done: done, Total: len(cards)}
+				switch {
+				case err != nil:
+					result.Failed = append(result.Failed, tcgdex.CardError{CardID: card.ID, Name: card.Name, Err: err})
+					event.Kind, event.Err = tcgdex.ProgressCardFailed, err
+				case card.Image == "":
+					result.NoImage++
+				case changed:
+					result.Downloaded++
+				default:
+					result.Unchanged++
+				}
+				if report != nil && ctx.Err() == nil {
+					report(event)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range cards {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- &cards[i]
+	}
+	close(jobs)
+	wg.Wait()
+
+	return result
+}
+
+// syncImage brings the stored image of one card up to date and reports
+// whether it was downloaded anew.
+func syncImage(ctx context.Context, client *tcgdex.Client, store *imagestore.Store, card *tcgdex.Card, quality tcgdex.ImageQuality, format tcgdex.ImageFormat) (bool, error) {
+	url := card.ImageURL(quality, format)
+	if url == "" {
+		return false, nil
+	}
+
+	// Only revalidate an image fetched from the same URL; a different quality
+	// or format is a different image.
+	var etag, lastModified string
+	if entry, ok := store.Entry(card.ID); ok && entry.URL == url {
+		etag, lastModified = entry.ETag, entry.LastModified
+	}
+
+	image, err := client.FetchImageContext(ctx, url, etag, lastModified)
+	if err != nil {
+		return false, err
+	}
+	if image.NotModified {
+		store.Touch(card.ID, image.ETag, image.LastModified)
+		return false, nil
+	}
+
+	_, changed, err := store.Put(card.ID, url, image.Data, image.ETag, image.LastModified)
+	if err != nil {
+		return false, fmt.Errorf("failed to store image: %w", err)
+	}
+	return changed, nil
+}
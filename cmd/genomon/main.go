@@ -2,11 +2,13 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"math/rand"
 	"os"
-	"sort"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cpritch/genomon/internal/core"
@@ -17,16 +19,73 @@ import (
 const (
 	rawOutputFile      = "ptcgp-cards.json"
 	enrichedOutputFile = "genomon-cards.json"
+	setsOutputFile     = "sets.json"
+	defaultCacheDir    = ".tcgdex-cache"
+	defaultImageDir    = "images"
 )
 
 func main() {
+	var syncOpts syncOptions
 	syncCmd := flag.NewFlagSet("sync", flag.ExitOnError)
-	syncOutputFile := syncCmd.String("o", rawOutputFile, "Output file for the synced card data")
+	syncCmd.StringVar(&syncOpts.outputFile, "o", rawOutputFile, "Output file for the synced card data")
+	syncCmd.StringVar(&syncOpts.setsFile, "sets", setsOutputFile, "Output file for set and booster metadata (empty to skip)")
+	syncCmd.StringVar(&syncOpts.baseURL, "base-url", tcgdex.DefaultBaseURL, "TCGdex API base URL, without the language")
+	syncCmd.Float64Var(&syncOpts.rate, "rps", tcgdex.DefaultRequestsPerSecond, "Maximum API requests per second (0 for unlimited)")
+	syncCmd.IntVar(&syncOpts.concurrency, "concurrency", tcgdex.DefaultConcurrency, "Maximum number of card requests in flight")
+	syncCmd.IntVar(&syncOpts.attempts, "attempts", tcgdex.DefaultRetryPolicy.MaxAttempts, "Maximum attempts per request before giving up")
+	syncCmd.StringVar(&syncOpts.cacheDir, "cache", defaultCacheDir, "Directory for cached API responses (empty to disable)")
+	syncCmd.BoolVar(&syncOpts.incremental, "incremental", false, "Only re-fetch sets whose card list changed since the last sync")
+	syncCmd.StringVar(&syncOpts.languages, "lang", "", "Comma-separated extra languages to fetch card text in (e.g. fr,de,ja)")
+	syncCmd.StringVar(&syncOpts.images, "images", "", "Also download card images in this quality: low or high (default: no images)")
+	syncCmd.StringVar(&syncOpts.imageFormat, "image-format", string(tcgdex.ImageWebP), "Card image format: png or webp")
+	syncCmd.StringVar(&syncOpts.imageDir, "image-dir", defaultImageDir, "Directory of the content-addressed image store")
 
 	processCmd := flag.NewFlagSet("process", flag.ExitOnError)
 	processInputFile := processCmd.String("i", rawOutputFile, "Input file for processing")
 	processOutputFile := processCmd.String("o", enrichedOutputFile, "Output file for processed data")
 	sampleSize := processCmd.Int("n", 0, "Number of random unknown effects to sample and print")
+	processRules := processCmd.String("rules", "", "Effect rule table to parse with (default: the built-in rules)")
+	processProvenance := processCmd.Bool("provenance", false, "Record on each parsed effect the rule and text it was parsed from")
+	processCoverage := processCmd.String("coverage", "", "Write a parser coverage report to this file, as Markdown if it ends in .md and JSON otherwise")
+	failOnUnknown := processCmd.Bool("fail-on-unknown", false, "Exit with status 1 if any effect text isn't fully parsed")
+
+	var diffOpts diffOptions
+	diffCmd := flag.NewFlagSet("diff", flag.ExitOnError)
+	diffCmd.StringVar(&diffOpts.enrichedFile, "enriched", enrichedOutputFile, "Processed card data to check for effects needing re-review (empty to skip)")
+	diffCmd.BoolVar(&diffOpts.asJSON, "json", false, "Print the changelog as JSON")
+
+	var reconcileOpts reconcileOptions
+	reconcileCmd := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	reconcileCmd.BoolVar(&reconcileOpts.asJSON, "json", false, "Print the report as JSON")
+	reconcileCmd.StringVar(&reconcileOpts.cacheDir, "cache", defaultCacheDir, "Directory for cached API responses when reading from tcgdex (empty to disable)")
+
+	migrateCmd := flag.NewFlagSet("migrate", flag.ExitOnError)
+	migrateInputFile := migrateCmd.String("i", enrichedOutputFile, "Processed card data to migrate")
+	migrateOutputFile := migrateCmd.String("o", "", "Output file for the migrated data (default: the input file)")
+
+	var lintOpts lintOptions
+	parserLintCmd := flag.NewFlagSet("parser lint", flag.ExitOnError)
+	parserLintCmd.StringVar(&lintOpts.inputFile, "i", rawOutputFile, "Card data whose effect texts the rules are checked against")
+	parserLintCmd.StringVar(&lintOpts.rulesFile, "rules", "", "Effect rule table to check (default: the built-in rules)")
+	parserLintCmd.BoolVar(&lintOpts.asJSON, "json", false, "Print the report as JSON")
+
+	var verifyOpts verifyOptions
+	parserVerifyCmd := flag.NewFlagSet("parser verify", flag.ExitOnError)
+	parserVerifyCmd.StringVar(&verifyOpts.inputFile, "i", enrichedOutputFile, "Processed card data whose parsed effects are verified")
+	parserVerifyCmd.BoolVar(&verifyOpts.asJSON, "json", false, "Print the losses as JSON")
+
+	var compareOpts compareOptions
+	parserCompareCmd := flag.NewFlagSet("parser compare", flag.ExitOnError)
+	parserCompareCmd.StringVar(&compareOpts.inputFile, "i", rawOutputFile, "Card data whose effect texts are parsed both ways")
+	parserCompareCmd.StringVar(&compareOpts.rulesFile, "rules", "", "Effect rule table to compare with (default: the built-in rules)")
+	parserCompareCmd.BoolVar(&compareOpts.all, "all", false, "Also list the texts only the rule table parses")
+	parserCompareCmd.BoolVar(&compareOpts.asJSON, "json", false, "Print the report as JSON")
+
+	var explainOpts explainOptions
+	explainCmd := flag.NewFlagSet("explain", flag.ExitOnError)
+	explainCmd.StringVar(&explainOpts.inputFile, "i", rawOutputFile, "Card data to find the cards in")
+	explainCmd.StringVar(&explainOpts.rulesFile, "rules", "", "Effect rule table to parse with (default: the built-in rules)")
+	explainCmd.BoolVar(&explainOpts.asJSON, "json", false, "Print the explanation as JSON")
 
 	if len(os.Args) < 2 {
 		printUsage()
@@ -36,10 +95,42 @@ func main() {
 	switch os.Args[1] {
 	case "sync":
 		syncCmd.Parse(os.Args[2:])
-		handleSyncCommand(syncOutputFile)
+		handleSyncCommand(syncOpts)
 	case "process":
 		processCmd.Parse(os.Args[2:])
-		handleProcessCommand(processInputFile, processOutputFile, sampleSize)
+		effects.SetProvenance(*processProvenance)
+		handleProcessCommand(processInputFile, processOutputFile, processRules, sampleSize, processCoverage, failOnUnknown)
+	case "diff":
+		diffCmd.Parse(os.Args[2:])
+		handleDiffCommand(diffOpts, diffCmd.Args())
+	case "reconcile":
+		reconcileCmd.Parse(os.Args[2:])
+		handleReconcileCommand(reconcileOpts, reconcileCmd.Args())
+	case "migrate":
+		migrateCmd.Parse(os.Args[2:])
+		handleMigrateCommand(*migrateInputFile, *migrateOutputFile)
+	case "explain":
+		explainCmd.Parse(os.Args[2:])
+		handleExplainCommand(explainOpts, explainCmd.Args())
+	case "parser":
+		if len(os.Args) < 3 {
+			printUsage()
+			os.Exit(1)
+		}
+		switch os.Args[2] {
+		case "lint":
+			parserLintCmd.Parse(os.Args[3:])
+			handleParserLintCommand(lintOpts)
+		case "verify":
+			parserVerifyCmd.Parse(os.Args[3:])
+			handleParserVerifyCommand(verifyOpts)
+		case "compare":
+			parserCompareCmd.Parse(os.Args[3:])
+			handleParserCompareCommand(compareOpts)
+		default:
+			printUsage()
+			os.Exit(1)
+		}
 	default:
 		fmt.Printf("Unknown command: %s\n", os.Args[1])
 		printUsage()
@@ -52,75 +143,67 @@ func printUsage() {
 	fmt.Println("\nCommands:")
 	fmt.Println("  sync       Fetches the latest card data from the TCGdex API.")
 	fmt.Println("    -o <file>    Output file for card data (default: ptcgp-cards.json)")
+	fmt.Println("    -sets <file>      Output file for set and booster metadata, empty to skip (default: sets.json)")
+	fmt.Println("    -base-url <url>   TCGdex API base URL, without the language (default: https://api.tcgdex.net/v2)")
+	fmt.Println("    -rps <n>     Maximum API requests per second, 0 for unlimited (default: 10)")
+	fmt.Println("    -concurrency <n>  Maximum card requests in flight (default: 4)")
+	fmt.Println("    -attempts <n>     Maximum attempts per request before giving up (default: 4)")
+	fmt.Println("    -cache <dir>      Directory for cached API responses, empty to disable (default: .tcgdex-cache)")
+	fmt.Println("    -incremental      Only re-fetch sets whose card list changed since the last sync")
+	fmt.Println("    -lang <list>      Extra languages to fetch card text in, e.g. fr,de,ja (default: none)")
+	fmt.Println("    -images <q>       Also download card images in quality low or high (default: no images)")
+	fmt.Println("    -image-format <f> Card image format, png or webp (default: webp)")
+	fmt.Println("    -image-dir <dir>  Directory of the content-addressed image store (default: images)")
 	fmt.Println("\n  process    Parses effects from raw card data into a structured format.")
 	fmt.Println("    -i <file>    Input file for processing (default: ptcgp-cards.json)")
 	fmt.Println("    -o <file>    Output file for processed data (default: genomon-cards.json)")
+	fmt.Println("    -n <count>   Number of random unknown effects to sample and print")
+	fmt.Println("    -rules <file>     Effect rule table to parse with (default: the built-in rules)")
+	fmt.Println("    -provenance       Record on each parsed effect the rule and text it was parsed from")
+	fmt.Println("    -coverage <file>  Write a parser coverage report, as Markdown (.md) or JSON")
+	fmt.Println("    -fail-on-unknown  Exit with status 1 if any effect text isn't fully parsed")
+	fmt.Println("\n  diff       Reports what changed between two synced card files.")
+	fmt.Println("    genomon diff [options] <old.json> <new.json>")
+	fmt.Println("    -enriched <file>  Processed data to flag effects needing re-review (default: genomon-cards.json)")
+	fmt.Println("    -json             Print the changelog as JSON")
+	fmt.Println("\n  reconcile  Reports where two card sources disagree on HP, costs and effects.")
+	fmt.Println("    genomon reconcile [options] <source-a> <source-b>")
+	fmt.Println("    Each source is a card file (sync, process or legacy cleaned-cards.json output) or \"tcgdex\" for the live API.")
+	fmt.Println("    -cache <dir>      Directory for cached API responses, empty to disable (default: .tcgdex-cache)")
+	fmt.Println("    -json             Print the report as JSON")
+	fmt.Println("\n  migrate    Upgrades processed card data to the current effect conditions layout.")
+	fmt.Println("    -i <file>    Processed card data to migrate (default: genomon-cards.json)")
+	fmt.Println("    -o <file>    Output file for the migrated data (default: the input file)")
+	fmt.Println("\n  explain    Shows which rules parsed the effects of the given cards, and from what text.")
+	fmt.Println("    genomon explain [options] <card-id>...")
+	fmt.Println("    -i <file>         Card data to find the cards in (default: ptcgp-cards.json)")
+	fmt.Println("    -rules <file>     Effect rule table to parse with (default: the built-in rules)")
+	fmt.Println("    -json             Print the explanation as JSON")
+	fmt.Println("\n  parser lint  Checks the effect rules for overlapping, unused and shadowed rules.")
+	fmt.Println("    -i <file>         Card data to check the rules against (default: ptcgp-cards.json)")
+	fmt.Println("    -rules <file>     Effect rule table to check (default: the built-in rules)")
+	fmt.Println("    -json             Print the report as JSON")
+	fmt.Println("\n  parser verify  Renders parsed effects back to text and lists texts whose parse loses information.")
+	fmt.Println("    -i <file>         Processed card data to verify (default: genomon-cards.json)")
+	fmt.Println("    -json             Print the losses as JSON")
+	fmt.Println("\n  parser compare  Parses effect texts with both the effect grammar and the rules, and lists where they differ.")
+	fmt.Println("    -i <file>         Card data whose effect texts are compared (default: ptcgp-cards.json)")
+	fmt.Println("    -rules <file>     Effect rule table to compare with (default: the built-in rules)")
+	fmt.Println("    -all              Also list the texts only the rule table parses")
+	fmt.Println("    -json             Print the report as JSON")
 }
 
-// ... existing handleSyncCommand code ...
-func handleSyncCommand(outputFile *string) {
-	fmt.Println("Starting card data sync from TCGdex...")
-	client := tcgdex.NewClient()
-
-	fmt.Println("Fetching TCG Pocket set list...")
-	pocketSetIDs, err := client.FetchTCGPSetIDs()
-	if err != nil {
-		fmt.Printf("Error fetching TCG Pocket set list: %v\n", err)
-		os.Exit(1)
-	}
-
-	if len(pocketSetIDs) == 0 {
-		fmt.Println("No TCG Pocket sets found. Exiting.")
-		os.Exit(0)
-	}
-
-	fmt.Printf("Found %d sets: %v\n", len(pocketSetIDs), pocketSetIDs)
-
-	allCards := make(map[string]tcgdex.Card) // Use a map to prevent duplicates
-
-	for _, setID := range pocketSetIDs {
-		fmt.Printf("\n--- Fetching set: %s ---\n", setID)
-		setCards, err := client.FetchSetCards(setID)
+func handleProcessCommand(inputFile, outputFile, rulesFile *string, sampleSize *int, coverageFile *string, failOnUnknown *bool) {
+	if *rulesFile != "" {
+		rules, err := effects.LoadRulesFile(*rulesFile)
 		if err != nil {
-			fmt.Printf("Error fetching set %s: %v\n", setID, err)
-			continue
-		}
-		for _, card := range setCards {
-			allCards[card.ID] = card
+			fmt.Printf("Error loading effect rules: %v\n", err)
+			os.Exit(1)
 		}
-		fmt.Printf("--- Finished set: %s ---\n", setID)
-	}
-
-	// Convert map to slice for sorting
-	cardSlice := make([]tcgdex.Card, 0, len(allCards))
-	for _, card := range allCards {
-		cardSlice = append(cardSlice, card)
+		effects.SetRules(rules)
+		fmt.Printf("Using %d effect rules from %s\n", len(rules.Rules()), *rulesFile)
 	}
 
-	// Sort cards by ID for a consistent output file
-	sort.Slice(cardSlice, func(i, j int) bool {
-		return cardSlice[i].ID < cardSlice[j].ID
-	})
-
-	fmt.Printf("\nTotal unique cards fetched: %d\n", len(cardSlice))
-
-	// Write the data to the output file
-	fileData, err := json.MarshalIndent(cardSlice, "", "  ")
-	if err != nil {
-		fmt.Printf("Error marshalling card data to JSON: %v\n", err)
-		os.Exit(1)
-	}
-
-	err = os.WriteFile(*outputFile, fileData, 0644)
-	if err != nil {
-		fmt.Printf("Error writing to output file %s: %v\n", *outputFile, err)
-		os.Exit(1)
-	}
-
-	fmt.Printf("Successfully synced all card data to %s\n", *outputFile)
-}
-
-func handleProcessCommand(inputFile, outputFile *string, sampleSize *int) {
 	fmt.Printf("Loading raw card data from %s...\n", *inputFile)
 	data, err := os.ReadFile(*inputFile)
 	if err != nil {
@@ -134,54 +217,78 @@ func handleProcessCommand(inputFile, outputFile *string, sampleSize *int) {
 		os.Exit(1)
 	}
 
-	fmt.Printf("Processing %d cards to parse effects...\n", len(rawCards))
-	enrichedCards := make([]core.Card, 0, len(rawCards))
-	var unknownCards []core.Card // Slice to store cards with unknown effects
-
+	// Trainer subtypes drive core game rules (one Supporter per turn, Tools
+	// attach, Fossils play as Pokémon), so point out cards without one.
+	var unrecognised []tcgdex.Card
 	for _, rawCard := range rawCards {
-		enrichedCard := core.Card{
-			Card:            rawCard,
-			ParsedAbilities: []core.Effect{},
-			ParsedAttacks:   []core.Effect{},
+		if rawCard.IsTrainer() && rawCard.TrainerKind() == tcgdex.TrainerUnknown {
+			unrecognised = append(unrecognised, rawCard)
 		}
+	}
+	if len(unrecognised) > 0 {
+		fmt.Printf("Warning: %d trainer card(s) have no recognised trainer type:\n", len(unrecognised))
+		for _, card := range unrecognised {
+			fmt.Printf("  └─ %s (%s): %q\n", card.Name, card.ID, card.TrainerType)
+		}
+		fmt.Println("Re-sync the card data to pick up trainer types.")
+	}
 
-		cardHasUnknownEffect := false
+	// Types, costs and weaknesses are written out in canonical form, and
+	// an energy type the simulator doesn't know is an error in the data.
+	var badEnergy []string
+	for i := range rawCards {
+		if err := core.NormalizeEnergy(&rawCards[i]); err != nil {
+			badEnergy = append(badEnergy, fmt.Sprintf("%s (%s): %v", rawCards[i].Name, rawCards[i].ID, err))
+		}
+	}
+	if len(badEnergy) > 0 {
+		fmt.Printf("Error: %d card(s) have unknown energy types:\n", len(badEnergy))
+		for _, line := range badEnergy {
+			fmt.Printf("  └─ %s\n", line)
+		}
+		os.Exit(1)
+	}
 
-		// Parse abilities
-		for _, ability := range rawCard.Abilities {
-			if ability.Effect != "" {
-				parsedEffects := effects.Parse(ability.Effect)
-				for _, effect := range parsedEffects {
-					effect.Name = ability.Name // Add name for mapping
-					if effect.Type == core.EffectUnknown {
-						cardHasUnknownEffect = true
-					}
-					enrichedCard.ParsedAbilities = append(enrichedCard.ParsedAbilities, effect)
-				}
+	fmt.Printf("Processing %d cards to parse effects...\n", len(rawCards))
+	enrichedCards := make([]core.Card, 0, len(rawCards))
+	var unknownCards []core.Card  // Slice to store cards with unknown effects
+	var damageMismatches []string // Attacks whose parsed effects contradict their printed damage
+	var partialTexts []string     // Effect texts with clauses no parsed effect accounts for
+	var validation []effects.Warning
+	validator := effects.NewValidator(rawCards)
+
+	for _, rawCard := range rawCards {
+		enrichedCard, problems := effects.ParseCard(rawCard)
+		for _, err := range problems {
+			line := fmt.Sprintf("%s (%s): %v", rawCard.Name, rawCard.ID, err)
+			var partial *effects.PartialError
+			if errors.As(err, &partial) {
+				partialTexts = append(partialTexts, line)
+			} else {
+				damageMismatches = append(damageMismatches, line)
 			}
 		}
 
-		// Parse attacks
-		for _, attack := range rawCard.Attacks {
-			if attack.Effect != "" {
-				parsedEffects := effects.Parse(attack.Effect)
-				for _, effect := range parsedEffects {
-					effect.Name = attack.Name // Add name for mapping
-					if effect.Type == core.EffectUnknown {
-						cardHasUnknownEffect = true
-					}
-					enrichedCard.ParsedAttacks = append(enrichedCard.ParsedAttacks, effect)
-				}
-			}
+		for _, t := range enrichedCard.UnknownEnergyTypes() {
+			badEnergy = append(badEnergy, fmt.Sprintf("%s (%s): parsed effect has unknown energy type %q", rawCard.Name, rawCard.ID, t))
 		}
 
-		if cardHasUnknownEffect {
+		if enrichedCard.HasUnknownEffect() {
 			unknownCards = append(unknownCards, enrichedCard)
 		}
+		validation = append(validation, validator.Validate(enrichedCard)...)
 
 		enrichedCards = append(enrichedCards, enrichedCard)
 	}
 
+	if len(badEnergy) > 0 {
+		fmt.Printf("Error: %d parsed effect(s) have unknown energy types:\n", len(badEnergy))
+		for _, line := range badEnergy {
+			fmt.Printf("  └─ %s\n", line)
+		}
+		os.Exit(1)
+	}
+
 	// Write the enriched data to the output file
 	fileData, err := json.MarshalIndent(enrichedCards, "", "  ")
 	if err != nil {
@@ -197,6 +304,22 @@ func handleProcessCommand(inputFile, outputFile *string, sampleSize *int) {
 
 	fmt.Printf("Successfully processed and saved enriched card data to %s\n", *outputFile)
 
+	if len(damageMismatches) > 0 {
+		fmt.Printf("\n⚠️  Warning: %d attack(s) have parsed effects that contradict their printed damage.\n", len(damageMismatches))
+		for _, mismatch := range damageMismatches {
+			fmt.Printf("  └─ %s\n", mismatch)
+		}
+	}
+
+	if len(partialTexts) > 0 {
+		fmt.Printf("\n⚠️  Warning: %d effect text(s) were only partly parsed.\n", len(partialTexts))
+		for _, partial := range partialTexts {
+			fmt.Printf("  └─ %s\n", partial)
+		}
+	}
+
+	printValidation(validation)
+
 	if len(unknownCards) > 0 {
 		fmt.Printf("\n⚠️  Warning: Could not parse one or more effects for %d card(s).\n", len(unknownCards))
 
@@ -225,8 +348,74 @@ func handleProcessCommand(inputFile, outputFile *string, sampleSize *int) {
 						fmt.Printf("  └─ Attack '%s': %s\n", effect.Name, effect.Description)
 					}
 				}
+				for _, effect := range card.ParsedTrainerEffects {
+					if effect.Type == core.EffectUnknown {
+						fmt.Printf("  └─ Trainer effect: %s\n", effect.Description)
+					}
+				}
 				fmt.Println()
 			}
 		}
 	}
+
+	if *coverageFile == "" && !*failOnUnknown {
+		return
+	}
+	coverage := effects.MeasureCoverage(rawCards)
+	if *coverageFile != "" {
+		if err := writeCoverage(*coverageFile, coverage); err != nil {
+			fmt.Printf("Error writing coverage report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\nParser coverage: %.1f%% of %d effect text(s); report saved to %s\n", coverage.Coverage, coverage.Texts, *coverageFile)
+	}
+	if *failOnUnknown && !coverage.Complete() {
+		fmt.Printf("\n❌ %d effect text(s) are not parsed and %d only partly parsed.\n", coverage.Unknown, coverage.Partial)
+		os.Exit(1)
+	}
+}
+
+// writeCoverage saves a coverage report to path, as Markdown if it ends in
+// .md and as JSON otherwise.
+func writeCoverage(path string, coverage *effects.Coverage) error {
+	data := []byte(coverage.Markdown())
+	if !strings.EqualFold(filepath.Ext(path), ".md") {
+		var err error
+		if data, err = json.MarshalIndent(coverage, "", "  "); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// printValidation lists the parsed effects that don't fit their cards,
+// errors before warnings.
+func printValidation(warnings []effects.Warning) {
+	for _, severity := range []effects.Severity{effects.SeverityError, effects.SeverityWarning} {
+		var lines []string
+		for _, warning := range warnings {
+			if warning.Severity != severity {
+				continue
+			}
+			source := fmt.Sprintf("%s (%s)", warning.CardName, warning.Card)
+			if warning.Name != "" {
+				source += fmt.Sprintf(" '%s'", warning.Name)
+			}
+			if warning.Effect != "" {
+				source += " " + string(warning.Effect)
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s", source, warning.Message))
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if severity == effects.SeverityError {
+			fmt.Printf("\n❌ Validation: %d parsed effect(s) can't be right for their card.\n", len(lines))
+		} else {
+			fmt.Printf("\n⚠️  Validation: %d parsed effect(s) look unusual for their card.\n", len(lines))
+		}
+		for _, line := range lines {
+			fmt.Printf("  └─ %s\n", line)
+		}
+	}
 }
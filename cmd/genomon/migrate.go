@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+// handleMigrateCommand rewrites processed card data whose effect conditions
+// use the legacy flat layout in the typed layout, without parsing the cards
+// again. Decoding a core.Card upgrades legacy conditions, so writing the
+// cards back out is the whole migration.
+func handleMigrateCommand(inputFile, outputFile string) {
+	if outputFile == "" {
+		outputFile = inputFile
+	}
+
+	data, err := os.ReadFile(inputFile)
+	if err != nil {
+		fmt.Printf("Error reading input file: %v\n", err)
+		os.Exit(1)
+	}
+	var cards []core.Card
+	if err := json.Unmarshal(data, &cards); err != nil {
+		fmt.Printf("Error migrating card data: %v\n", err)
+		os.Exit(1)
+	}
+
+	migrated, err := json.MarshalIndent(cards, "", "  ")
+	if err != nil {
+		fmt.Printf("Error marshalling card data to JSON: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(outputFile, migrated, 0644); err != nil {
+		fmt.Printf("Error writing to output file %s: %v\n", outputFile, err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Migrated the effects of %d card(s) from %s to %s\n", len(cards), inputFile, outputFile)
+}
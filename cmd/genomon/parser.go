@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/internal/effects"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// lintOptions holds the flags accepted by the parser lint command.
+type lintOptions struct {
+	inputFile string
+	rulesFile string
+	asJSON    bool
+}
+
+// handleParserLintCommand checks the effect rules against every effect text
+// in the card data, exiting with an error if any rule can never take effect.
+func handleParserLintCommand(opts lintOptions) {
+	rules := effects.DefaultRules()
+	if opts.rulesFile != "" {
+		var err error
+		if rules, err = effects.LoadRulesFile(opts.rulesFile); err != nil {
+			fmt.Printf("Error loading effect rules: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	data, err := os.ReadFile(opts.inputFile)
+	if err != nil {
+		fmt.Printf("Error reading input file: %v\n", err)
+		os.Exit(1)
+	}
+	var cards []tcgdex.Card
+	if err := json.Unmarshal(data, &cards); err != nil {
+		fmt.Printf("Error unmarshalling card data: %v\n", err)
+		os.Exit(1)
+	}
+
+	report := rules.Lint(effects.EffectTexts(cards))
+	if opts.asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Printf("Error marshalling report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		printLintReport(report, len(rules.Rules()))
+	}
+	if report.Problems() {
+		os.Exit(1)
+	}
+}
+
+func printLintReport(report *effects.LintReport, rules int) {
+	fmt.Printf("Checked %d rules against %d effect texts and clauses.\n", rules, report.Texts)
+
+	if len(report.Ambiguous) > 0 {
+		conflicting := 0
+		for _, match := range report.Ambiguous {
+			if match.Conflicting {
+				conflicting++
+			}
+		}
+		fmt.Printf("\n%d text(s) match more than one rule, %d of them with different results. The first rule listed is used.\n", len(report.Ambiguous), conflicting)
+		for _, match := range report.Ambiguous {
+			marker := ""
+			if match.Conflicting {
+				marker = " (differs)"
+			}
+			fmt.Printf("  └─ %q%s\n", match.Text, marker)
+			for _, name := range match.Rules {
+				fmt.Printf("       - %s\n", name)
+			}
+		}
+	}
+
+	if len(report.Unused) > 0 {
+		fmt.Printf("\n❌ %d rule(s) match no effect text:\n", len(report.Unused))
+		for _, name := range report.Unused {
+			fmt.Printf("  └─ %s\n", name)
+		}
+	}
+
+	if len(report.Shadowed) > 0 {
+		fmt.Printf("\n❌ %d rule(s) only match texts an earlier rule already matches:\n", len(report.Shadowed))
+		for _, shadowed := range report.Shadowed {
+			fmt.Printf("  └─ %s, shadowed by %q\n", shadowed.Rule, shadowed.By)
+			fmt.Printf("       e.g. %q\n", shadowed.Example)
+		}
+	}
+
+	if !report.Problems() {
+		fmt.Println("\n✅ Every rule parses at least one effect text.")
+	}
+}
+
+// verifyOptions holds the flags accepted by the parser verify command.
+type verifyOptions struct {
+	inputFile string
+	asJSON    bool
+}
+
+// handleParserVerifyCommand renders the parsed effects in processed card
+// data back to text, exiting with an error if any text loses information in
+// parsing.
+func handleParserVerifyCommand(opts verifyOptions) {
+	data, err := os.ReadFile(opts.inputFile)
+	if err != nil {
+		fmt.Printf("Error reading input file: %v\n", err)
+		os.Exit(1)
+	}
+	var cards []core.Card
+	if err := json.Unmarshal(data, &cards); err != nil {
+		fmt.Printf("Error unmarshalling card data: %v\n", err)
+		os.Exit(1)
+	}
+
+	losses := effects.Verify(cards)
+	if opts.asJSON {
+		if losses == nil {
+			losses = []effects.Loss{}
+		}
+		data, err := json.MarshalIndent(losses, "", "  ")
+		if err != nil {
+			fmt.Printf("Error marshalling losses: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		printLosses(losses, len(cards))
+	}
+	if len(losses) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printLosses(losses []effects.Loss, cards int) {
+	if len(losses) == 0 {
+		fmt.Printf("✅ The parsed effects of all %d cards render back to everything their text says.\n", cards)
+		return
+	}
+
+	fmt.Printf("❌ %d effect text(s) lose information in parsing:\n", len(losses))
+	for _, loss := range losses {
+		name := loss.CardName
+		if loss.Name != "" {
+			name += " '" + loss.Name + "'"
+		}
+		fmt.Printf("  └─ %s (%s): missing %s\n", name, loss.Card, strings.Join(loss.Missing, ", "))
+		fmt.Printf("       text:     %s\n", strings.Join(strings.Fields(loss.Text), " "))
+		fmt.Printf("       rendered: %s\n", loss.Rendered)
+	}
+}
+
+// compareOptions holds the flags accepted by the parser compare command.
+type compareOptions struct {
+	inputFile string
+	rulesFile string
+	all       bool
+	asJSON    bool
+}
+
+// handleParserCompareCommand parses every ability, attack and Trainer card
+// text in the card data with both the effect grammar and the rule table, and reports
+// where they disagree. Disagreements are expected while rules are migrated
+// to the grammar, so they don't fail the command.
+func handleParserCompareCommand(opts compareOptions) {
+	if opts.rulesFile != "" {
+		rules, err := effects.LoadRulesFile(opts.rulesFile)
+		if err != nil {
+			fmt.Printf("Error loading effect rules: %v\n", err)
+			os.Exit(1)
+		}
+		effects.SetRules(rules)
+	}
+
+	data, err := os.ReadFile(opts.inputFile)
+	if err != nil {
+		fmt.Printf("Error reading input file: %v\n", err)
+		os.Exit(1)
+	}
+	var cards []tcgdex.Card
+	if err := json.Unmarshal(data, &cards); err != nil {
+		fmt.Printf("Error unmarshalling card data: %v\n", err)
+		os.Exit(1)
+	}
+
+	comparison := effects.CompareGrammar(cards)
+	if !opts.all {
+		results := comparison.Results[:0]
+		for _, result := range comparison.Results {
+			if result.Outcome != effects.GrammarRulesOnly {
+				results = append(results, result)
+			}
+		}
+		comparison.Results = results
+	}
+	if opts.asJSON {
+		data, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			fmt.Printf("Error marshalling report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	printGrammarComparison(comparison)
+}
+
+func printGrammarComparison(comparison *effects.GrammarComparison) {
+	counts := comparison.Counts
+	fmt.Printf("Compared the grammar with the rules on %d effect texts.\n", comparison.Texts)
+	fmt.Printf("  ✅ %d parse the same\n", counts[effects.GrammarSame])
+	fmt.Printf("  ❌ %d parse differently\n", counts[effects.GrammarDifferent])
+	fmt.Printf("  ⚠️ %d parse only with the grammar\n", counts[effects.GrammarOnly])
+	fmt.Printf("  %d parse only with the rules, and %d with neither\n", counts[effects.GrammarRulesOnly], counts[effects.GrammarNeither])
+
+	var outcome effects.GrammarOutcome
+	for _, result := range comparison.Results {
+		if result.Outcome != outcome {
+			outcome = result.Outcome
+			fmt.Printf("\n%s:\n", outcome)
+		}
+		fmt.Printf("  └─ %q\n", result.Text)
+		if len(result.Differences) > 0 {
+			fmt.Printf("       differs in %s\n", strings.Join(result.Differences, ", "))
+		}
+		if result.Error != "" {
+			fmt.Printf("       grammar: %s\n", result.Error)
+		}
+	}
+}
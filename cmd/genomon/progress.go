@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+const progressBarWidth = 30
+
+// progressPrinter renders tcgdex progress events. On an interactive terminal
+// it redraws a single progress bar per set; otherwise only failures and
+// retries are printed so logs stay short.
+type progressPrinter struct {
+	out         *os.File
+	interactive bool
+	drawn       bool // Whether a partially drawn bar is on the current line
+}
+
+func newProgressPrinter(out *os.File) *progressPrinter {
+	interactive := false
+	if info, err := out.Stat(); err == nil {
+		interactive = info.Mode()&os.ModeCharDevice != 0
+	}
+	return &progressPrinter{out: out, interactive: interactive}
+}
+
+// Handle is a tcgdex.ProgressFunc.
+func (p *progressPrinter) Handle(event tcgdex.ProgressEvent) {
+	switch event.Kind {
+	case tcgdex.ProgressRetrying:
+		p.clearLine()
+		fmt.Fprintf(p.out, "Retrying %s in %s (attempt %d failed): %v\n", event.Resource, event.Delay.Round(1e6), event.Attempt, event.Err)
+	case tcgdex.ProgressCardFailed:
+		p.clearLine()
+		fmt.Fprintf(p.out, "Warning: failed to fetch card %s: %v\n", event.CardID, event.Err)
+	}
+
+	if event.Kind == tcgdex.ProgressRetrying || !p.interactive || event.Total == 0 {
+		return
+	}
+
+	filled := event.Done * progressBarWidth / event.Total
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	fmt.Fprintf(p.out, "\r\033[K%s [%s] %d/%d %s", event.SetID, bar, event.Done, event.Total, event.CardName)
+	p.drawn = true
+	if event.Done == event.Total {
+		p.Finish()
+	}
+}
+
+// Finish ends any partially drawn progress bar line.
+func (p *progressPrinter) Finish() {
+	if p.drawn {
+		fmt.Fprintln(p.out)
+		p.drawn = false
+	}
+}
+
+// clearLine erases a partially drawn bar so a message can be printed in its place.
+func (p *progressPrinter) clearLine() {
+	if p.drawn {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.drawn = false
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cpritch/genomon/internal/source"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// liveSourceName selects the TCGdex API rather than a file as a reconcile source.
+const liveSourceName = "tcgdex"
+
+// reconcileOptions holds the flags accepted by the reconcile command.
+type reconcileOptions struct {
+	asJSON   bool
+	cacheDir string
+}
+
+func handleReconcileCommand(opts reconcileOptions, args []string) {
+	if len(args) != 2 {
+		fmt.Println("Usage: genomon reconcile [options] <source-a> <source-b>")
+		os.Exit(1)
+	}
+
+	a, err := openCardSource(args[0], opts)
+	if err != nil {
+		fmt.Printf("Error opening %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	b, err := openCardSource(args[1], opts)
+	if err != nil {
+		fmt.Printf("Error opening %s: %v\n", args[1], err)
+		os.Exit(1)
+	}
+
+	report, err := source.Reconcile(context.Background(), a, b)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if opts.asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Printf("Error marshalling report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	printReconcileReport(report)
+}
+
+// openCardSource returns the TCGdex API source for "tcgdex" and a file source
+// for anything else.
+func openCardSource(name string, opts reconcileOptions) (source.CardSource, error) {
+	if name != liveSourceName {
+		return source.NewFile(name), nil
+	}
+
+	var clientOpts []tcgdex.Option
+	if opts.cacheDir != "" {
+		cache, err := tcgdex.NewCache(opts.cacheDir)
+		if err != nil {
+			return nil, err
+		}
+		clientOpts = append(clientOpts, tcgdex.WithCache(cache))
+	}
+	return source.NewTCGdex(tcgdex.NewClient(clientOpts...)), nil
+}
+
+func printReconcileReport(report *source.Report) {
+	fmt.Printf("Compared %d cards in both %s (A) and %s (B).\n", report.Compared, report.A, report.B)
+	if len(report.OnlyInA) > 0 {
+		fmt.Printf("%d card(s) only in A\n", len(report.OnlyInA))
+	}
+	if len(report.OnlyInB) > 0 {
+		fmt.Printf("%d card(s) only in B\n", len(report.OnlyInB))
+	}
+
+	if len(report.Cards) == 0 {
+		fmt.Println("\n✅ The sources agree on every card they share.")
+		return
+	}
+
+	fmt.Printf("\n⚠️  The sources disagree about %d card(s):\n", len(report.Cards))
+	for _, card := range report.Cards {
+		fmt.Printf("\n%s (%s)\n", card.Name, card.ID)
+		for _, d := range card.Disagreements {
+			fmt.Printf("  └─ %s\n       A: %s\n       B: %s\n", d.Field, orNone(d.A), orNone(d.B))
+		}
+	}
+}
+
+// orNone shows an empty value as "(none)".
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/cpritch/genomon/internal/imagestore"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// syncOptions holds the flags accepted by the sync command.
+type syncOptions struct {
+	outputFile  string
+	setsFile    string
+	baseURL     string
+	rate        float64
+	concurrency int
+	attempts    int
+	cacheDir    string
+	incremental bool
+	languages   string // Comma-separated extra languages to fetch text in
+	images      string // Image quality to download, or empty to skip images
+	imageFormat string
+	imageDir    string
+}
+
+// syncResult is the outcome of syncing every set in the series.
+type syncResult struct {
+	Cards       []tcgdex.Card      // Every card fetched, sorted by ID
+	Sets        []tcgdex.SetInfo   // Metadata of every set fetched, in series order
+	FailedSets  []error            // Sets that could not be listed at all
+	FailedCards []tcgdex.CardError // Cards that failed even after retrying
+}
+
+// Complete reports whether every set and card was fetched successfully.
+func (r *syncResult) Complete() bool {
+	return len(r.FailedSets) == 0 && len(r.FailedCards) == 0
+}
+
+func handleSyncCommand(opts syncOptions) {
+	// Ctrl-C cancels the sync; whatever was fetched is saved as a checkpoint.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var quality tcgdex.ImageQuality
+	var format tcgdex.ImageFormat
+	if opts.images != "" {
+		var err error
+		if quality, err = tcgdex.ParseImageQuality(opts.images); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if format, err = tcgdex.ParseImageFormat(opts.imageFormat); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println("Starting card data sync from TCGdex...")
+	progress := newProgressPrinter(os.Stdout)
+	retry := tcgdex.DefaultRetryPolicy
+	retry.MaxAttempts = opts.attempts
+	clientOpts := []tcgdex.Option{
+		tcgdex.WithBaseURL(opts.baseURL),
+		tcgdex.WithRateLimit(opts.rate, opts.concurrency),
+		tcgdex.WithConcurrency(opts.concurrency),
+		tcgdex.WithRetryPolicy(retry),
+		tcgdex.WithProgress(progress.Handle),
+	}
+	if opts.cacheDir != "" {
+		cache, err := tcgdex.NewCache(opts.cacheDir)
+		if err != nil {
+			fmt.Printf("Error opening response cache: %v\n", err)
+			os.Exit(1)
+		}
+		clientOpts = append(clientOpts, tcgdex.WithCache(cache))
+	}
+	client := tcgdex.NewClient(clientOpts...)
+
+	// Each extra language gets its own client sharing the same settings.
+	var translators []*tcgdex.Client
+	for _, language := range strings.Split(opts.languages, ",") {
+		language = strings.TrimSpace(language)
+		if language == "" || language == tcgdex.DefaultLanguage {
+			continue
+		}
+		translators = append(translators, tcgdex.NewClient(append(clientOpts, tcgdex.WithLanguage(language))...))
+	}
+
+	checkpointFile := checkpointPath(opts.outputFile)
+	var previous []tcgdex.Card
+	if opts.incremental {
+		previous = loadPreviousSync(opts.outputFile)
+	}
+
+	result, err := syncCards(ctx, client, translators, previous)
+	progress.Finish()
+	if ctx.Err() != nil {
+		fmt.Printf("\nInterrupted. Saving %d fetched cards to %s...\n", len(result.Cards), checkpointFile)
+		if err := writeJSONFile(checkpointFile, result.Cards); err != nil {
+			fmt.Printf("Error writing checkpoint: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Re-run with -incremental to resume from the checkpoint.")
+		os.Exit(130)
+	}
+	if err != nil {
+		fmt.Printf("Error fetching TCG Pocket set list: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(result.Cards) == 0 && result.Complete() {
+		fmt.Println("No TCG Pocket sets found. Exiting.")
+		os.Exit(0)
+	}
+
+	fmt.Printf("\nTotal unique cards fetched: %d\n", len(result.Cards))
+
+	// Refuse to overwrite the output with an incomplete dataset
+	if !result.Complete() {
+		fmt.Printf("\n❌ Sync incomplete: %d set(s) and %d card(s) could not be fetched.\n", len(result.FailedSets), len(result.FailedCards))
+		for _, err := range result.FailedSets {
+			fmt.Printf("  └─ %v\n", err)
+		}
+		for _, cardErr := range result.FailedCards {
+			fmt.Printf("  └─ %v\n", &cardErr)
+		}
+		fmt.Printf("%s was left unchanged.\n", opts.outputFile)
+		os.Exit(1)
+	}
+
+	// Write the data to the output file
+	if err := writeJSONFile(opts.outputFile, result.Cards); err != nil {
+		fmt.Printf("Error writing to output file %s: %v\n", opts.outputFile, err)
+		os.Exit(1)
+	}
+	os.Remove(checkpointFile) // The checkpoint is superseded by a complete sync
+
+	fmt.Printf("Successfully synced all card data to %s\n", opts.outputFile)
+
+	if opts.setsFile != "" {
+		if err := writeJSONFile(opts.setsFile, result.Sets); err != nil {
+			fmt.Printf("Error writing set metadata to %s: %v\n", opts.setsFile, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved metadata for %d sets to %s\n", len(result.Sets), opts.setsFile)
+	}
+
+	if quality != "" {
+		handleImageSync(ctx, client, result.Cards, quality, format, opts, progress)
+	}
+}
+
+// handleImageSync downloads card images into the image store as part of the
+// sync command, saving the manifest even if interrupted.
+func handleImageSync(ctx context.Context, client *tcgdex.Client, cards []tcgdex.Card, quality tcgdex.ImageQuality, format tcgdex.ImageFormat, opts syncOptions, progress *progressPrinter) {
+	store, err := imagestore.Open(opts.imageDir)
+	if err != nil {
+		fmt.Printf("Error opening image store: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nSyncing %s quality %s images to %s...\n", quality, format, opts.imageDir)
+	images := syncImages(ctx, client, store, cards, quality, format, opts.concurrency, progress.Handle)
+	progress.Finish()
+	if err := store.Save(); err != nil {
+		fmt.Printf("Error saving image manifest: %v\n", err)
+		os.Exit(1)
+	}
+	if ctx.Err() != nil {
+		fmt.Println("\nInterrupted. Images downloaded so far were saved; re-run to fetch the rest.")
+		os.Exit(130)
+	}
+
+	fmt.Printf("Images: %d downloaded, %d unchanged, %d cards without artwork\n", images.Downloaded, images.Unchanged, images.NoImage)
+	if pruned, err := store.Prune(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	} else if pruned > 0 {
+		fmt.Printf("Removed %d image(s) no longer used by any card\n", pruned)
+	}
+	if len(images.Failed) > 0 {
+		fmt.Printf("\n❌ %d image(s) could not be fetched.\n", len(images.Failed))
+		for _, cardErr := range images.Failed {
+			fmt.Printf("  └─ %v\n", &cardErr)
+		}
+		os.Exit(1)
+	}
+}
+
+// syncCards fetches every card in the client's series, and the same cards from
+// each translator client to fill in their Localized text. When previous holds
+// the output of an earlier sync, sets whose card list hasn't changed are carried
+// over from it without fetching any of their cards. An error is returned only
+// if the set list itself cannot be fetched; per-set and per-card failures are
+// recorded in the result. If ctx is cancelled, the cards fetched so far are
+// returned along with ctx's error.
+func syncCards(ctx context.Context, client *tcgdex.Client, translators []*tcgdex.Client, previous []tcgdex.Card) (*syncResult, error) {
+	// Index the previous output by set so unchanged sets can be reused.
+	previousBySet := make(map[string][]tcgdex.Card)
+	for _, card := range previous {
+		previousBySet[card.Set.ID] = append(previousBySet[card.Set.ID], card)
+	}
+
+	fmt.Println("Fetching TCG Pocket set list...")
+	pocketSetIDs, err := client.FetchTCGPSetIDsContext(ctx)
+	if err != nil {
+		return &syncResult{}, err
+	}
+
+	fmt.Printf("Found %d sets: %v\n", len(pocketSetIDs), pocketSetIDs)
+
+	result := &syncResult{}
+	allCards := make(map[string]tcgdex.Card) // Use a map to prevent duplicates
+
+	for _, setID := range pocketSetIDs {
+		fmt.Printf("\n--- Fetching set: %s ---\n", setID)
+		setDetails, err := client.FetchSetContext(ctx, setID)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			fmt.Printf("Error fetching set %s: %v\n", setID, err)
+			result.FailedSets = append(result.FailedSets, fmt.Errorf("set %s: %w", setID, err))
+			continue
+		}
+
+		setCards, reused := previousBySet[setID]
+		if reused && sameCardList(setDetails.Cards, setCards) {
+			fmt.Printf("Card list unchanged, reusing %d previously synced cards\n", len(setCards))
+		} else {
+			reused = false
+			setCards, err = client.FetchCardsInSetContext(ctx, setDetails)
+			if ctx.Err() != nil {
+				addCards(allCards, setCards)
+				break
+			}
+			if err != nil {
+				var incomplete *tcgdex.IncompleteSetError
+				if !errors.As(err, &incomplete) {
+					fmt.Printf("Error fetching set %s: %v\n", setID, err)
+					result.FailedSets = append(result.FailedSets, fmt.Errorf("set %s: %w", setID, err))
+					continue
+				}
+				result.FailedCards = append(result.FailedCards, incomplete.Failed...)
+			}
+		}
+
+		for _, translator := range translators {
+			language := translator.Language()
+			if reused && hasLocalization(setCards, language) {
+				continue
+			}
+			fmt.Printf("Fetching %s text for set %s\n", language, setID)
+			failed := localizeCards(ctx, translator, setDetails, setCards)
+			result.FailedCards = append(result.FailedCards, failed...)
+		}
+
+		addCards(allCards, setCards)
+		if ctx.Err() != nil {
+			break
+		}
+		result.Sets = append(result.Sets, tcgdex.NewSetInfo(setDetails, setCards))
+		fmt.Printf("--- Finished set: %s ---\n", setID)
+	}
+
+	// Convert map to slice for sorting
+	result.Cards = make([]tcgdex.Card, 0, len(allCards))
+	for _, card := range allCards {
+		result.Cards = append(result.Cards, card)
+	}
+
+	// Sort cards by ID for a consistent output file
+	sort.Slice(result.Cards, func(i, j int) bool {
+		return result.Cards[i].ID < result.Cards[j].ID
+	})
+
+	return result, ctx.Err()
+}
+
+// checkpointPath returns where an interrupted sync to outputFile saves the
+// cards it fetched.
+func checkpointPath(outputFile string) string {
+	return outputFile + ".partial"
+}
+
+// loadPreviousSync reads the cards of an earlier sync to outputFile for an
+// incremental sync, resuming from an interrupted sync's checkpoint by
+// preferring its fresher cards.
+func loadPreviousSync(outputFile string) []tcgdex.Card {
+	previous, err := loadRawCards(outputFile)
+	checkpointFile := checkpointPath(outputFile)
+	checkpoint, checkpointErr := loadRawCards(checkpointFile)
+	switch {
+	case checkpointErr == nil && err != nil:
+		fmt.Printf("Resuming from checkpoint %s (%d cards); no previous sync in %s: %v\n", checkpointFile, len(checkpoint), outputFile, err)
+	case checkpointErr == nil:
+		fmt.Printf("Resuming from checkpoint %s (%d cards)\n", checkpointFile, len(checkpoint))
+	case err != nil:
+		fmt.Printf("Warning: cannot read previous sync from %s, doing a full sync: %v\n", outputFile, err)
+	}
+	if checkpointErr == nil {
+		previous = mergeCards(previous, checkpoint)
+	}
+	return previous
+}
+
+// addCards adds cards to a map keyed by card ID.
+func addCards(byID map[string]tcgdex.Card, cards []tcgdex.Card) {
+	for _, card := range cards {
+		byID[card.ID] = card
+	}
+}
+
+// mergeCards returns the union of base and overrides, taking cards from
+// overrides where both contain the same ID.
+func mergeCards(base, overrides []tcgdex.Card) []tcgdex.Card {
+	byID := make(map[string]tcgdex.Card, len(base)+len(overrides))
+	addCards(byID, base)
+	addCards(byID, overrides)
+
+	merged := make([]tcgdex.Card, 0, len(byID))
+	for _, card := range byID {
+		merged = append(merged, card)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ID < merged[j].ID
+	})
+	return merged
+}
+
+// writeJSONFile writes v to path as indented JSON.
+func writeJSONFile(path string, v interface{}) error {
+	fileData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return os.WriteFile(path, fileData, 0644)
+}
+
+// localizeCards fetches the cards of a set from translator and records their
+// text as a localization on the matching entries of cards. Cards that simply
+// don't exist in the translator's language are skipped; any other failure is
+// returned.
+func localizeCards(ctx context.Context, translator *tcgdex.Client, setDetails *tcgdex.SetDetails, cards []tcgdex.Card) []tcgdex.CardError {
+	translated, err := translator.FetchCardsInSetContext(ctx, setDetails)
+
+	var failed []tcgdex.CardError
+	if err != nil && ctx.Err() == nil {
+		var incomplete *tcgdex.IncompleteSetError
+		if !errors.As(err, &incomplete) {
+			return []tcgdex.CardError{{CardID: setDetails.ID, Name: setDetails.Name, Err: err}}
+		}
+		for _, cardErr := range incomplete.Failed {
+			var statusErr *tcgdex.StatusError
+			if errors.As(cardErr.Err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+				continue // Not translated into this language
+			}
+			failed = append(failed, cardErr)
+		}
+	}
+
+	byID := make(map[string]*tcgdex.Card, len(translated))
+	for i := range translated {
+		byID[translated[i].ID] = &translated[i]
+	}
+	for i := range cards {
+		if t, ok := byID[cards[i].ID]; ok {
+			cards[i].AddLocalization(translator.Language(), t)
+		}
+	}
+	return failed
+}
+
+// hasLocalization reports whether any of cards already has text in language.
+func hasLocalization(cards []tcgdex.Card, language string) bool {
+	for _, card := range cards {
+		if _, ok := card.Localized[language]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// loadRawCards reads a card file previously written by the sync command.
+func loadRawCards(path string) ([]tcgdex.Card, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []tcgdex.Card
+	if err := json.Unmarshal(data, &cards); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// sameCardList reports whether a set listing contains exactly the cards that
+// were synced for it previously.
+func sameCardList(listed []tcgdex.CardStump, previous []tcgdex.Card) bool {
+	if len(listed) != len(previous) {
+		return false
+	}
+	seen := make(map[string]bool, len(previous))
+	for _, card := range previous {
+		seen[card.ID] = true
+	}
+	for _, stump := range listed {
+		if !seen[stump.ID] {
+			return false
+		}
+	}
+	return true
+}
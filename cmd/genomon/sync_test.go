@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/internal/imagestore"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+	"github.com/cpritch/genomon/pkg/tcgdex/tcgdextest"
+)
+
+func TestSyncCardsOffline(t *testing.T) {
+	cards, err := tcgdextest.LoadFixtures("../../" + rawOutputFile)
+	if err != nil {
+		t.Fatalf("loading fixtures: %v", err)
+	}
+	server := tcgdextest.NewServer(cards)
+	defer server.Close()
+
+	client := tcgdex.NewClient(
+		tcgdex.WithBaseURL(server.BaseURL()),
+		tcgdex.WithRateLimit(0, 0),
+		tcgdex.WithConcurrency(16),
+	)
+
+	result, err := syncCards(context.Background(), client, nil, nil)
+	if err != nil {
+		t.Fatalf("syncCards: %v", err)
+	}
+	if !result.Complete() {
+		t.Fatalf("sync incomplete: %v %v", result.FailedSets, result.FailedCards)
+	}
+	if !reflect.DeepEqual(result.Cards, cards) {
+		t.Errorf("synced %d cards that differ from the %d fixtures", len(result.Cards), len(cards))
+	}
+	if len(result.Sets) != 11 || result.Sets[0].ID != "A1" || len(result.Sets[0].CardIDs) != 286 {
+		t.Errorf("unexpected set metadata: %d sets, first %s", len(result.Sets), result.Sets[0].ID)
+	}
+
+	// An incremental sync against unchanged sets must not fetch any cards.
+	before := server.Requests("/v2/en/cards/A1-001")
+	again, err := syncCards(context.Background(), client, nil, result.Cards)
+	if err != nil {
+		t.Fatalf("incremental syncCards: %v", err)
+	}
+	if !reflect.DeepEqual(again.Cards, cards) {
+		t.Errorf("incremental sync returned %d cards that differ from the fixtures", len(again.Cards))
+	}
+	if after := server.Requests("/v2/en/cards/A1-001"); after != before {
+		t.Errorf("incremental sync re-fetched an unchanged card")
+	}
+}
+
+func TestSyncCardsLocalized(t *testing.T) {
+	cards, err := tcgdextest.LoadFixtures("../../" + rawOutputFile)
+	if err != nil {
+		t.Fatalf("loading fixtures: %v", err)
+	}
+	cards = cards[:2]
+	server := tcgdextest.NewServer(cards)
+	defer server.Close()
+
+	// Only the first card has a French translation.
+	french := cards[0]
+	french.Name = "Bulbizarre"
+	french.Attacks = []tcgdex.Attack{{Name: "Fouet Lianes"}}
+	server.AddCards("fr", []tcgdex.Card{french})
+
+	opts := []tcgdex.Option{tcgdex.WithBaseURL(server.BaseURL()), tcgdex.WithRateLimit(0, 0)}
+	client := tcgdex.NewClient(opts...)
+	translator := tcgdex.NewClient(append(opts, tcgdex.WithLanguage("fr"))...)
+
+	result, err := syncCards(context.Background(), client, []*tcgdex.Client{translator}, nil)
+	if err != nil {
+		t.Fatalf("syncCards: %v", err)
+	}
+	if !result.Complete() {
+		t.Fatalf("a missing translation should not make the sync incomplete: %v", result.FailedCards)
+	}
+
+	got := result.Cards[0].Translation("fr")
+	if got.Name != "Bulbizarre" || got.Attacks[0].Name != "Fouet Lianes" {
+		t.Errorf("French translation = %+v", got)
+	}
+	if result.Cards[0].Name != "Bulbasaur" {
+		t.Errorf("canonical name changed to %q", result.Cards[0].Name)
+	}
+	if got := result.Cards[1].Translation("fr"); got.Name != result.Cards[1].Name {
+		t.Errorf("untranslated card should fall back to English, got %q", got.Name)
+	}
+}
+
+func TestSyncImages(t *testing.T) {
+	cards, err := tcgdextest.LoadFixtures("../../" + rawOutputFile)
+	if err != nil {
+		t.Fatalf("loading fixtures: %v", err)
+	}
+	cards = cards[:3]
+	server := tcgdextest.NewServer(cards)
+	defer server.Close()
+
+	// The first two cards share artwork; the third has none.
+	for i := range cards[:2] {
+		cards[i].Image = server.URL + "/assets/en/tcgp/A1/" + cards[i].LocalID
+		server.AddImage("/assets/en/tcgp/A1/"+cards[i].LocalID+"/low.webp", []byte("shared artwork"))
+	}
+	cards[2].Image = ""
+
+	client := tcgdex.NewClient(tcgdex.WithRateLimit(0, 0))
+	store, err := imagestore.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("opening store: %v", err)
+	}
+
+	result := syncImages(context.Background(), client, store, cards, tcgdex.ImageLow, tcgdex.ImageWebP, 2, nil)
+	if len(result.Failed) > 0 || result.Downloaded != 2 || result.NoImage != 1 {
+		t.Fatalf("first sync = %+v", result)
+	}
+	first, _ := store.Entry(cards[0].ID)
+	second, _ := store.Entry(cards[1].ID)
+	if first.Path == "" || first.Path != second.Path {
+		t.Errorf("identical images should share one object, got %q and %q", first.Path, second.Path)
+	}
+
+	// Unchanged images are revalidated, not re-downloaded; a changed one is replaced.
+	server.AddImage("/assets/en/tcgp/A1/"+cards[1].LocalID+"/low.webp", []byte("new artwork"))
+	result = syncImages(context.Background(), client, store, cards, tcgdex.ImageLow, tcgdex.ImageWebP, 2, nil)
+	if len(result.Failed) > 0 || result.Downloaded != 1 || result.Unchanged != 1 {
+		t.Fatalf("second sync = %+v", result)
+	}
+	if second, _ := store.Entry(cards[1].ID); second.Path == first.Path {
+		t.Errorf("changed image still points at the old object")
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("saving manifest: %v", err)
+	}
+	reopened, err := imagestore.Open(store.Dir())
+	if err != nil {
+		t.Fatalf("reopening store: %v", err)
+	}
+	if got, ok := reopened.Entry(cards[0].ID); !ok || got.SHA256 != first.SHA256 {
+		t.Errorf("manifest entry after reopening = %+v", got)
+	}
+}
+
+func TestSyncCardsResumesFromCheckpoint(t *testing.T) {
+	fixtures, err := tcgdextest.LoadFixtures("../../" + rawOutputFile)
+	if err != nil {
+		t.Fatalf("loading fixtures: %v", err)
+	}
+	// Five cards from each of the first two sets.
+	var cards []tcgdex.Card
+	perSet := make(map[string]int)
+	for _, card := range fixtures {
+		if (card.Set.ID == "A1" || card.Set.ID == "A1a") && perSet[card.Set.ID] < 5 {
+			cards = append(cards, card)
+			perSet[card.Set.ID]++
+		}
+	}
+	server := tcgdextest.NewServer(cards)
+	defer server.Close()
+
+	// Interrupt the sync, as Ctrl-C would, after the second card of A1a.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := tcgdex.NewClient(
+		tcgdex.WithBaseURL(server.BaseURL()),
+		tcgdex.WithRateLimit(0, 0),
+		tcgdex.WithConcurrency(1),
+		tcgdex.WithProgress(func(event tcgdex.ProgressEvent) {
+			if event.Kind == tcgdex.ProgressCardFetched && event.SetID == "A1a" && event.Done == 2 {
+				cancel()
+			}
+		}),
+	)
+	interrupted, err := syncCards(ctx, client, nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted syncCards error = %v, want context.Canceled", err)
+	}
+	if n := len(interrupted.Cards); n < 7 || n >= len(cards) {
+		t.Fatalf("interrupted sync kept %d cards, want all of A1 and part of A1a", n)
+	}
+	if !reflect.DeepEqual(interrupted.Cards[:5], cards[:5]) {
+		t.Errorf("interrupted sync lost cards of the finished set A1")
+	}
+
+	outputFile := filepath.Join(t.TempDir(), rawOutputFile)
+	if err := writeJSONFile(checkpointPath(outputFile), interrupted.Cards); err != nil {
+		t.Fatalf("writing checkpoint: %v", err)
+	}
+	checkpoint, err := loadRawCards(checkpointPath(outputFile))
+	if err != nil {
+		t.Fatalf("reading checkpoint: %v", err)
+	}
+	if !reflect.DeepEqual(checkpoint, interrupted.Cards) {
+		t.Errorf("checkpoint holds %d cards, want the %d fetched before the interruption", len(checkpoint), len(interrupted.Cards))
+	}
+
+	// An -incremental run with no previous output resumes from the
+	// checkpoint: A1 is reused and only A1a is fetched again.
+	previous := loadPreviousSync(outputFile)
+	if !reflect.DeepEqual(previous, checkpoint) {
+		t.Fatalf("loadPreviousSync returned %d cards, want the %d in the checkpoint", len(previous), len(checkpoint))
+	}
+	before := server.Requests("/v2/en/cards/A1-001")
+	resumed, err := syncCards(context.Background(), client, nil, previous)
+	if err != nil {
+		t.Fatalf("resumed syncCards: %v", err)
+	}
+	if !resumed.Complete() || !reflect.DeepEqual(resumed.Cards, cards) {
+		t.Errorf("resumed sync returned %d cards, want all %d", len(resumed.Cards), len(cards))
+	}
+	if after := server.Requests("/v2/en/cards/A1-001"); after != before {
+		t.Errorf("resumed sync re-fetched a card from the checkpoint")
+	}
+}
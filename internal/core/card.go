@@ -1,11 +1,130 @@
 package core
 
-import "github.com/cpritch/genomon/pkg/tcgdex"
+import (
+	"fmt"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
 
 // Card represents our internal, enriched representation of a card. It embeds
 // the raw card data from the TCGdex API and adds our own parsed effects.
 type Card struct {
 	tcgdex.Card
-	ParsedAbilities []Effect `json:"parsedAbilities"`
-	ParsedAttacks   []Effect `json:"parsedAttacks"`
+	ParsedAbilities      []Effect `json:"parsedAbilities"`
+	ParsedAttacks        []Effect `json:"parsedAttacks"`
+	ParsedTrainerEffects []Effect `json:"parsedTrainerEffects,omitempty"` // Parsed from Text, for Trainer cards only
+}
+
+// HasUnknownEffect reports whether any of the card's effects failed to parse.
+func (c *Card) HasUnknownEffect() bool {
+	for _, effect := range c.ParsedAbilities {
+		if effect.Type == EffectUnknown {
+			return true
+		}
+	}
+	for _, effect := range c.ParsedAttacks {
+		if effect.Type == EffectUnknown {
+			return true
+		}
+	}
+	for _, effect := range c.ParsedTrainerEffects {
+		if effect.Type == EffectUnknown {
+			return true
+		}
+	}
+	return false
+}
+
+// UnknownEnergyTypes returns the energy types in the card's effects that
+// aren't one of EnergyTypes, which only a parser bug can produce.
+func (c *Card) UnknownEnergyTypes() []EnergyType {
+	var unknown []EnergyType
+	for _, effects := range [][]Effect{c.ParsedAbilities, c.ParsedAttacks, c.ParsedTrainerEffects} {
+		for _, effect := range effects {
+			for _, t := range effect.Conditions.EnergyTypes() {
+				if !t.Known() {
+					unknown = append(unknown, t)
+				}
+			}
+		}
+	}
+	return unknown
+}
+
+// PokemonTypes returns the card's types. Like Cost and WeaknessTypes, it
+// reads both names and symbols, and keeps a value that isn't an energy type
+// as it is, so Known reports it.
+func (c *Card) PokemonTypes() []EnergyType {
+	return energyTypes(c.Types)
+}
+
+// Cost returns the energy cost of the card's i-th attack.
+func (c *Card) Cost(i int) []EnergyType {
+	return energyTypes(c.Attacks[i].Cost)
+}
+
+// WeaknessTypes returns the type of each of the card's weaknesses.
+func (c *Card) WeaknessTypes() []EnergyType {
+	if c.Weaknesses == nil {
+		return nil
+	}
+	types := make([]EnergyType, len(c.Weaknesses))
+	for i, weakness := range c.Weaknesses {
+		types[i] = energyType(weakness.Type)
+	}
+	return types
+}
+
+func energyTypes(values []string) []EnergyType {
+	if values == nil {
+		return nil
+	}
+	types := make([]EnergyType, len(values))
+	for i, value := range values {
+		types[i] = energyType(value)
+	}
+	return types
+}
+
+func energyType(value string) EnergyType {
+	if t, err := ParseEnergyType(value); err == nil {
+		return t
+	}
+	return EnergyType(value)
+}
+
+// NormalizeEnergy rewrites the card's types, attack costs and weaknesses as
+// EnergyType values, failing on any value that isn't an energy type.
+func NormalizeEnergy(card *tcgdex.Card) error {
+	types, err := ParseEnergyTypes(card.Types)
+	if err != nil {
+		return fmt.Errorf("types: %w", err)
+	}
+	card.Types = energyNames(types)
+	for i := range card.Attacks {
+		cost, err := ParseEnergyTypes(card.Attacks[i].Cost)
+		if err != nil {
+			return fmt.Errorf("attack %q cost: %w", card.Attacks[i].Name, err)
+		}
+		card.Attacks[i].Cost = energyNames(cost)
+	}
+	for i := range card.Weaknesses {
+		t, err := ParseEnergyType(card.Weaknesses[i].Type)
+		if err != nil {
+			return fmt.Errorf("weakness: %w", err)
+		}
+		card.Weaknesses[i].Type = string(t)
+	}
+	return nil
+}
+
+func energyNames(types []EnergyType) []string {
+	if types == nil {
+		return nil
+	}
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return names
 }
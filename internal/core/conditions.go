@@ -0,0 +1,304 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Conditions qualifies an Effect: when it happens, which Pokémon or cards it
+// applies to, where things move and how its amount scales. Every part is
+// optional, and a nil part doesn't apply.
+type Conditions struct {
+	CoinFlip    *CoinFlip    `json:"coinFlip,omitempty"`
+	Duration    Duration     `json:"duration,omitempty"`
+	Trigger     *Trigger     `json:"trigger,omitempty"`
+	Requirement *Requirement `json:"requirement,omitempty"`
+	Source      *Source      `json:"source,omitempty"`
+	Destination *Destination `json:"destination,omitempty"`
+	Scaling     *Scaling     `json:"scaling,omitempty"`
+	Filter      *Filter      `json:"filter,omitempty"`   // The Pokémon or cards the effect applies to or picks from
+	Opponent    *Filter      `json:"opponent,omitempty"` // What the opponent's Active Pokémon must be for the effect to apply
+	Attacker    *Filter      `json:"attacker,omitempty"` // The attacking Pokémon a defensive effect applies to
+	Energy      *Energy      `json:"energy,omitempty"`
+	Modifier    *Modifier    `json:"modifier,omitempty"`
+
+	// Choice is the option, counting from 1, of a "Choose 1:" Trainer card
+	// that the effect belongs to.
+	Choice int `json:"choice,omitempty"`
+	// DependsOn is the index, among the effects parsed from the same text, of
+	// the effect whose coin flip or outcome this one depends on.
+	DependsOn *int `json:"dependsOn,omitempty"`
+}
+
+// CoinFlip describes the coin flips an effect depends on.
+type CoinFlip struct {
+	Result CoinResult `json:"result,omitempty"` // The result the effect needs
+	Flips  int        `json:"flips,omitempty"`  // The number of coins, if more than 1
+	// FlipsPer is what the number of coins counts, such as
+	// SELF_ATTACHED_ENERGY for "flip a coin for each Energy attached".
+	FlipsPer string  `json:"flipsPer,omitempty"`
+	Chance   float64 `json:"chance,omitempty"` // The chance of Result, where it is stated
+	// Heads is the least number of heads the effect needs, as in "if at
+	// least 2 of them are heads".
+	Heads int `json:"heads,omitempty"`
+}
+
+// CoinResult is the outcome of a coin flip that an effect needs.
+type CoinResult string
+
+const (
+	CoinHeads       CoinResult = "HEADS"
+	CoinTails       CoinResult = "TAILS"
+	CoinDoubleHeads CoinResult = "DOUBLE_HEADS" // Both of 2 coins are heads
+)
+
+// Duration is how long an effect lasts.
+type Duration string
+
+const (
+	DurationThisTurn         Duration = "THIS_TURN"
+	DurationNextTurn         Duration = "NEXT_TURN"          // The player's own next turn
+	DurationOpponentNextTurn Duration = "OPPONENT_NEXT_TURN" // The opponent's next turn
+	DurationFirstTurn        Duration = "FIRST_TURN"
+	DurationPersistent       Duration = "PERSISTENT"
+	DurationPersistentActive Duration = "PERSISTENT_ACTIVE" // Until the Pokémon leaves the Active Spot
+)
+
+// UnmarshalJSON accepts durations in any case, as earlier output wrote some of
+// them in lower case.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*d = Duration(strings.ToUpper(s))
+	return nil
+}
+
+// Trigger is the event or game state that sets an effect off, along with
+// whatever the event needs to match.
+type Trigger struct {
+	Event      TriggerEvent    `json:"event,omitempty"`
+	Phase      string          `json:"phase,omitempty"`      // A phase of the turn, such as CHECKUP
+	AttackName string          `json:"attackName,omitempty"` // For ATTACK_USED_LAST_TURN
+	Status     StatusCondition `json:"status,omitempty"`     // For OPPONENT_HAS_STATUS
+	Property   string          `json:"property,omitempty"`   // For OPPONENT_HAS_PROPERTY
+	Name       string          `json:"name,omitempty"`       // For OPPONENT_IS_NAME and POKEMON_ON_BENCH
+	Stage      string          `json:"stage,omitempty"`      // For OPPONENT_IS_STAGE
+	Type       EnergyType      `json:"type,omitempty"`       // For DISCARDED_CARD_IS_TYPE
+	Count      int             `json:"count,omitempty"`      // For DIFFERENT_ENERGY_TYPES_ATTACHED
+}
+
+// TriggerEvent names what sets an effect off.
+type TriggerEvent string
+
+const (
+	TriggerOncePerTurn                  TriggerEvent = "ONCE_PER_TURN"
+	TriggerAsOftenAsYouLike             TriggerEvent = "AS_OFTEN_AS_YOU_LIKE"
+	TriggerEndOfTurn                    TriggerEvent = "END_OF_TURN"
+	TriggerEndOfFirstTurn               TriggerEvent = "END_OF_FIRST_TURN"
+	TriggerEndOfOpponentNextTurn        TriggerEvent = "END_OF_OPPONENT_NEXT_TURN"
+	TriggerKnockedOut                   TriggerEvent = "KNOCKED_OUT"
+	TriggerOnEvolve                     TriggerEvent = "ON_EVOLVE"
+	TriggerOnPlayToBench                TriggerEvent = "ON_PLAY_TO_BENCH"
+	TriggerAttachEnergyToSelf           TriggerEvent = "ATTACH_ENERGY_TO_SELF"
+	TriggerAttackUsedLastTurn           TriggerEvent = "ATTACK_USED_LAST_TURN"
+	TriggerDamagedLastTurn              TriggerEvent = "DAMAGED_LAST_TURN"
+	TriggerFriendlyKOLastTurn           TriggerEvent = "FRIENDLY_KO_LAST_TURN"
+	TriggerEvolvedThisTurn              TriggerEvent = "EVOLVED_THIS_TURN"
+	TriggerSwitchedInThisTurn           TriggerEvent = "SWITCHED_IN_THIS_TURN"
+	TriggerPlayedSupporterThisTurn      TriggerEvent = "PLAYED_SUPPORTER_THIS_TURN"
+	TriggerDiscardedCardIsType          TriggerEvent = "DISCARDED_CARD_IS_TYPE"
+	TriggerDifferentEnergyTypesAttached TriggerEvent = "DIFFERENT_ENERGY_TYPES_ATTACHED"
+	TriggerHasEnergyAttached            TriggerEvent = "HAS_ENERGY_ATTACHED"
+	TriggerSelfHasTypedEnergy           TriggerEvent = "SELF_HAS_TYPED_ENERGY"
+	TriggerSelfHasDamage                TriggerEvent = "SELF_HAS_DAMAGE"
+	TriggerSelfHasNoDamage              TriggerEvent = "SELF_HAS_NO_DAMAGE"
+	TriggerSelfHasTool                  TriggerEvent = "SELF_HAS_TOOL"
+	TriggerAnyBenchedFriendlyHasDamage  TriggerEvent = "ANY_BENCHED_FRIENDLY_HAS_DAMAGE"
+	TriggerPokemonOnBench               TriggerEvent = "POKEMON_ON_BENCH"
+	TriggerOpponentHasAbility           TriggerEvent = "OPPONENT_HAS_ABILITY"
+	TriggerOpponentHasDamage            TriggerEvent = "OPPONENT_HAS_DAMAGE"
+	TriggerOpponentHasProperty          TriggerEvent = "OPPONENT_HAS_PROPERTY"
+	TriggerOpponentHasSpecialCondition  TriggerEvent = "OPPONENT_HAS_SPECIAL_CONDITION"
+	TriggerOpponentHasStatus            TriggerEvent = "OPPONENT_HAS_STATUS"
+	TriggerOpponentHasTool              TriggerEvent = "OPPONENT_HAS_TOOL"
+	TriggerOpponentHPGreater            TriggerEvent = "OPPONENT_HP_GREATER"
+	TriggerOpponentIsEvolved            TriggerEvent = "OPPONENT_IS_EVOLVED"
+	TriggerOpponentIsEX                 TriggerEvent = "OPPONENT_IS_EX"
+	TriggerOpponentIsName               TriggerEvent = "OPPONENT_IS_NAME"
+	TriggerOpponentIsStage              TriggerEvent = "OPPONENT_IS_STAGE"
+	TriggerOpponentKO                   TriggerEvent = "OPPONENT_KO"
+)
+
+// Requirement is what must be true for an effect to be usable at all.
+type Requirement struct {
+	Text     string   `json:"text,omitempty"`     // A requirement kept as printed, such as "you have Araquanid in play"
+	Location Zone     `json:"location,omitempty"` // Where the Pokémon with the effect must be
+	InPlay   []string `json:"inPlay,omitempty"`   // Names of which the player must have at least 1 in play
+	// EnergyType and ExtraEnergy are Energy that must be attached beyond
+	// what the attack costs.
+	EnergyType        EnergyType `json:"energyType,omitempty"`
+	ExtraEnergy       int        `json:"extraEnergy,omitempty"`
+	AttackEnergy      bool       `json:"attackEnergy,omitempty"`    // The Pokémon must have the Energy for a copied attack
+	DiscardFromHand   int        `json:"discardFromHand,omitempty"` // Cards to discard from the hand to use the effect
+	NotFirstTurn      bool       `json:"notFirstTurn,omitempty"`
+	NotPlayedThisTurn bool       `json:"notPlayedThisTurn,omitempty"` // The target can't have been put into play this turn
+}
+
+// Zone is a place cards or Pokémon can be in.
+type Zone string
+
+const (
+	ZoneActive      Zone = "ACTIVE"
+	ZoneBench       Zone = "BENCH"
+	ZoneHand        Zone = "HAND"
+	ZoneDeck        Zone = "DECK"
+	ZoneDiscardPile Zone = "DISCARD_PILE"
+	ZoneEnergyZone  Zone = "ENERGY_ZONE"
+	ZoneSelf        Zone = "SELF" // The Pokémon with the effect
+)
+
+// Source is where an effect takes Energy, damage or cards from.
+type Source struct {
+	Zone    Zone       `json:"zone,omitempty"`
+	Pool    string     `json:"pool,omitempty"`    // The group of Pokémon taken from, such as ANY_FRIENDLY_DAMAGED
+	Type    EnergyType `json:"type,omitempty"`    // The type the source Pokémon must be
+	Subtype string     `json:"subtype,omitempty"` // The subtype the source Pokémon must be, such as Ultra Beast
+}
+
+// Destination is where an effect puts Energy, damage or cards.
+type Destination struct {
+	Zone Zone       `json:"zone,omitempty"`
+	Type EnergyType `json:"type,omitempty"` // The type the destination Pokémon must be
+	// OnMatch and Otherwise say where looked-at cards go when they do and
+	// don't match the effect's filter.
+	OnMatch   string `json:"onMatch,omitempty"`
+	Otherwise string `json:"otherwise,omitempty"`
+}
+
+// Scaling describes how an effect's amount is multiplied.
+type Scaling struct {
+	// By is what the amount is multiplied by, such as COIN_FLIP_HEADS or
+	// BENCHED_POKEMON_COUNT.
+	By    string     `json:"by"`
+	Type  EnergyType `json:"type,omitempty"`  // Only count things of this type
+	Names []string   `json:"names,omitempty"` // Only count Pokémon with these names
+	Max   int        `json:"max,omitempty"`   // The most that can be counted
+	// Evolved is true if only Evolution Pokémon are counted.
+	Evolved bool `json:"evolved,omitempty"`
+	// Base is true if the scaled amount is the attack's whole damage rather
+	// than damage on top of it.
+	Base bool `json:"base,omitempty"`
+	// Equals is true if the amount equals By rather than being a multiple of
+	// it, as in "damage equal to the damage this Pokémon has on it".
+	Equals bool `json:"equals,omitempty"`
+}
+
+// Filter narrows down which Pokémon or cards an effect applies to.
+type Filter struct {
+	Player         string       `json:"player,omitempty"` // SELF, OPPONENT, EITHER or BOTH
+	Pool           string       `json:"pool,omitempty"`   // The group picked from, such as ANY_OPPONENT or BENCHED
+	Location       Zone         `json:"location,omitempty"`
+	All            bool         `json:"all,omitempty"` // Every matching Pokémon, not just 1
+	Type           EnergyType   `json:"type,omitempty"`
+	Types          []EnergyType `json:"types,omitempty"` // Any of these types
+	Stage          string       `json:"stage,omitempty"`
+	Subtype        string       `json:"subtype,omitempty"` // Such as "ex" or "Ultra Beast"
+	Names          []string     `json:"names,omitempty"`
+	ExcludeName    string       `json:"excludeName,omitempty"`
+	EvolvesFrom    string       `json:"evolvesFrom,omitempty"`
+	EvolutionStage string       `json:"evolutionStage,omitempty"` // The stage of the card evolved into
+	Condition      string       `json:"condition,omitempty"`      // Such as DAMAGED or HAS_ENERGY_ATTACHED
+	Energy         EnergyType   `json:"energy,omitempty"`         // An energy type the Pokémon must have attached
+	CardType       string       `json:"cardType,omitempty"`       // Such as Supporter or Pokémon Tool
+	AttackName     string       `json:"attackName,omitempty"`     // The attack the effect applies to
+}
+
+// Energy describes the Energy an effect attaches, moves, discards or changes.
+type Energy struct {
+	Type             EnergyType   `json:"type,omitempty"`
+	Types            []EnergyType `json:"types,omitempty"`         // One of each of these types
+	PossibleTypes    []EnergyType `json:"possibleTypes,omitempty"` // Types picked from, at random or by the player
+	Random           bool         `json:"random,omitempty"`        // A random Energy is picked
+	RandomType       bool         `json:"randomType,omitempty"`    // The new type is picked at random
+	Next             bool         `json:"next,omitempty"`          // The next Energy generated in the Energy Zone
+	DistributeFreely bool         `json:"distributeFreely,omitempty"`
+}
+
+// Modifier holds the details of what an effect does that don't fit its type,
+// target and amount, mostly for passive abilities and restrictions.
+type Modifier struct {
+	// Effect is what a passive ability or Pokémon Tool does, such as
+	// REDUCE_INCOMING_DAMAGE.
+	Effect      string `json:"effect,omitempty"`
+	Restriction string `json:"restriction,omitempty"` // Such as CANT_ATTACK
+	Prevent     string `json:"prevent,omitempty"`     // Such as ALL_DAMAGE
+	Amount      int    `json:"amount,omitempty"`      // The size of a passive or Tool effect
+	All         bool   `json:"all,omitempty"`         // The effect applies to all of something, such as all damage
+	// CostAmount and CostType give an alternative attack cost.
+	CostAmount       int               `json:"costAmount,omitempty"`
+	CostType         EnergyType        `json:"costType,omitempty"`
+	Statuses         []StatusCondition `json:"statuses,omitempty"`
+	AllStatuses      bool              `json:"allStatuses,omitempty"`
+	PossibleStatuses []StatusCondition `json:"possibleStatuses,omitempty"` // Picked from at random
+	Hits             int               `json:"hits,omitempty"`             // How many times a random target is picked
+	RemainingHP      int               `json:"remainingHP,omitempty"`
+	DrawUntil        string            `json:"drawUntil,omitempty"`
+	Random           bool              `json:"random,omitempty"`
+	PlayerChooses    bool              `json:"playerChooses,omitempty"`
+	Optional         bool              `json:"optional,omitempty"`
+	Reveal           bool              `json:"reveal,omitempty"`
+	RevealHand       bool              `json:"revealHand,omitempty"`
+	MayShuffle       bool              `json:"mayShuffle,omitempty"`
+	Stacks           bool              `json:"stacks,omitempty"`
+	EndsTurn         bool              `json:"endsTurn,omitempty"`
+	CantRetreat      bool              `json:"cantRetreat,omitempty"`
+	DiscardAnyTime   bool              `json:"discardAnyTime,omitempty"` // The card can be discarded from play at any time
+	DiscardTool      bool              `json:"discardTool,omitempty"`    // The Tool is discarded after the effect
+}
+
+// EnergyTypes returns every energy type the conditions mention.
+func (c *Conditions) EnergyTypes() []EnergyType {
+	if c == nil {
+		return nil
+	}
+	var types []EnergyType
+	add := func(values ...EnergyType) {
+		for _, t := range values {
+			if t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+	if c.Trigger != nil {
+		add(c.Trigger.Type)
+	}
+	if c.Requirement != nil {
+		add(c.Requirement.EnergyType)
+	}
+	if c.Source != nil {
+		add(c.Source.Type)
+	}
+	if c.Destination != nil {
+		add(c.Destination.Type)
+	}
+	if c.Scaling != nil {
+		add(c.Scaling.Type)
+	}
+	for _, filter := range []*Filter{c.Filter, c.Opponent, c.Attacker} {
+		if filter != nil {
+			add(filter.Type, filter.Energy)
+			add(filter.Types...)
+		}
+	}
+	if c.Energy != nil {
+		add(c.Energy.Type)
+		add(c.Energy.Types...)
+		add(c.Energy.PossibleTypes...)
+	}
+	if c.Modifier != nil {
+		add(c.Modifier.CostType)
+	}
+	return types
+}
@@ -2,13 +2,28 @@ package core
 
 // Effect is our internal structured representation of an attack or ability's effect.
 type Effect struct {
-	Name        string                 `json:"name"`
-	Type        EffectType             `json:"type"`
-	Target      TargetType             `json:"target,omitempty"`
-	Status      StatusCondition        `json:"status,omitempty"`
-	Amount      int                    `json:"amount,omitempty"`
-	Conditions  map[string]interface{} `json:"conditions,omitempty"`
-	Description string                 `json:"description"`
+	Name        string          `json:"name"`
+	Type        EffectType      `json:"type"`
+	Target      TargetType      `json:"target,omitempty"`
+	Status      StatusCondition `json:"status,omitempty"`
+	Amount      int             `json:"amount,omitempty"`
+	Conditions  *Conditions     `json:"conditions,omitempty"`
+	Description string          `json:"description"`
+	// Provenance records how the parser arrived at the effect. It is only
+	// filled in on request, as it changes with every edit to the rules.
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// Provenance records which parser rule produced an effect, and from what text.
+type Provenance struct {
+	Rule string `json:"rule"` // The name of the rule
+	Text string `json:"text"` // The text the rule's pattern matched
+	// Span is the start and end byte offsets of Text in the card text the
+	// effect was parsed from, or empty if the rule matched clauses that
+	// aren't next to each other there.
+	Span     []int    `json:"span,omitempty"`
+	Captures []string `json:"captures,omitempty"` // The values of the pattern's capture groups, as normalised text
+	Version  string   `json:"version"`            // The parser and rule table that parsed the effect
 }
 
 // EffectType is an enum for the different kinds of effects we can parse.
@@ -64,6 +79,21 @@ const (
 	EffectDiscardBenched           EffectType = "DISCARD_BENCHED"
 	EffectDevolve                  EffectType = "DEVOLVE"
 	EffectDebuffIncomingDamage     EffectType = "DEBUFF_INCOMING_DAMAGE"
+
+	// Trainer-only mechanics
+	EffectPlayAsBasic         EffectType = "PLAY_AS_BASIC"         // Fossils
+	EffectToolAttachment      EffectType = "TOOL_ATTACHMENT"       // What a Pokémon Tool gives the Pokémon it is attached to
+	EffectShuffleHandAndDraw  EffectType = "SHUFFLE_HAND_AND_DRAW" // Hand refresh Supporters such as Iono
+	EffectBuffDamage          EffectType = "BUFF_DAMAGE"
+	EffectReduceRetreatCost   EffectType = "REDUCE_RETREAT_COST"
+	EffectReduceAttackCost    EffectType = "REDUCE_ATTACK_COST"
+	EffectRecoverStatus       EffectType = "RECOVER_STATUS"
+	EffectRecoverFromDiscard  EffectType = "RECOVER_FROM_DISCARD"
+	EffectEvolveSkippingStage EffectType = "EVOLVE_SKIPPING_STAGE" // Rare Candy
+	EffectSwapWithDeck        EffectType = "SWAP_WITH_DECK"
+	EffectRearrangeDeck       EffectType = "REARRANGE_DECK"
+	EffectCopySupporter       EffectType = "COPY_SUPPORTER"
+	EffectGuaranteeHeads      EffectType = "GUARANTEE_HEADS"
 )
 
 // TargetType defines who the effect applies to.
@@ -80,6 +110,8 @@ const (
 	TargetBenchedOpponentAll TargetType = "BENCHED_OPPONENT_ALL"
 	TargetDeck               TargetType = "DECK"
 	TargetEnergyZone         TargetType = "ENERGY_ZONE"
+	TargetActiveFriendly     TargetType = "ACTIVE_FRIENDLY"
+	TargetAttached           TargetType = "ATTACHED" // The Pokémon a Tool is attached to
 )
 
 // StatusCondition represents the special conditions in the game.
@@ -92,3 +124,14 @@ const (
 	StatusBurned    StatusCondition = "BURNED"
 	StatusParalyzed StatusCondition = "PARALYZED"
 )
+
+// ModifiesDamage reports whether effects of this type change the damage an
+// attack does beyond its printed base value, as signalled by a "+" or "×"
+// after the attack's damage.
+func (t EffectType) ModifiesDamage() bool {
+	switch t {
+	case EffectConditionalDamage, EffectScalingDamage:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EnergyType is a type of Energy, which is also the type of a Pokémon. Its
+// value is the name card data uses, such as "Fire".
+type EnergyType string
+
+const (
+	EnergyGrass     EnergyType = "Grass"
+	EnergyFire      EnergyType = "Fire"
+	EnergyWater     EnergyType = "Water"
+	EnergyLightning EnergyType = "Lightning"
+	EnergyPsychic   EnergyType = "Psychic"
+	EnergyFighting  EnergyType = "Fighting"
+	EnergyDarkness  EnergyType = "Darkness"
+	EnergyMetal     EnergyType = "Metal"
+	EnergyDragon    EnergyType = "Dragon" // A Pokémon type only; there is no Dragon Energy
+	EnergyColorless EnergyType = "Colorless"
+)
+
+// EnergyTypes lists every energy type, in the order the game lists them.
+var EnergyTypes = []EnergyType{
+	EnergyGrass, EnergyFire, EnergyWater, EnergyLightning, EnergyPsychic,
+	EnergyFighting, EnergyDarkness, EnergyMetal, EnergyDragon, EnergyColorless,
+}
+
+// energySymbols maps each energy type to the letter card text writes it as,
+// in braces, such as "{R}" for Fire.
+var energySymbols = map[EnergyType]string{
+	EnergyGrass:     "G",
+	EnergyFire:      "R",
+	EnergyWater:     "W",
+	EnergyLightning: "L",
+	EnergyPsychic:   "P",
+	EnergyFighting:  "F",
+	EnergyDarkness:  "D",
+	EnergyMetal:     "M",
+	EnergyDragon:    "N",
+	EnergyColorless: "C",
+}
+
+// ParseEnergyType reads an energy type written as a symbol, with or without
+// braces ("R" or "{R}"), or as a name in any case ("Fire" or "FIRE").
+func ParseEnergyType(s string) (EnergyType, error) {
+	symbol := strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	for _, t := range EnergyTypes {
+		if symbol == energySymbols[t] || strings.EqualFold(s, string(t)) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown energy type %q", s)
+}
+
+// ParseEnergyTypes parses each of values with ParseEnergyType.
+func ParseEnergyTypes(values []string) ([]EnergyType, error) {
+	if values == nil {
+		return nil, nil
+	}
+	types := make([]EnergyType, len(values))
+	for i, value := range values {
+		t, err := ParseEnergyType(value)
+		if err != nil {
+			return nil, err
+		}
+		types[i] = t
+	}
+	return types, nil
+}
+
+// Known reports whether t is one of EnergyTypes.
+func (t EnergyType) Known() bool {
+	_, ok := energySymbols[t]
+	return ok
+}
+
+// Symbol returns the letter card text writes t as, such as "R" for Fire.
+func (t EnergyType) Symbol() string {
+	return energySymbols[t]
+}
+
+// UnmarshalJSON reads an energy type in any form ParseEnergyType accepts, so
+// that effects written with symbols decode to the same values.
+func (t *EnergyType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseEnergyType(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+func TestParseEnergyType(t *testing.T) {
+	for _, s := range []string{"R", "{R}", "Fire", "fire", "FIRE"} {
+		if got, err := ParseEnergyType(s); got != EnergyFire || err != nil {
+			t.Errorf("ParseEnergyType(%q) = %q, %v; want Fire", s, got, err)
+		}
+	}
+	for _, s := range []string{"", "X", "{X}", "Fir", "r"} {
+		if got, err := ParseEnergyType(s); err == nil {
+			t.Errorf("ParseEnergyType(%q) = %q, want an error", s, got)
+		}
+	}
+	for _, energy := range EnergyTypes {
+		if got, err := ParseEnergyType(energy.Symbol()); got != energy || err != nil {
+			t.Errorf("ParseEnergyType(%q) = %q, %v; want %s", energy.Symbol(), got, err, energy)
+		}
+	}
+
+	var energy Energy
+	if err := json.Unmarshal([]byte(`{"type": "W", "possibleTypes": ["{G}", "Metal"]}`), &energy); err != nil {
+		t.Fatal(err)
+	}
+	if energy.Type != EnergyWater || len(energy.PossibleTypes) != 2 || energy.PossibleTypes[0] != EnergyGrass || energy.PossibleTypes[1] != EnergyMetal {
+		t.Errorf("decoded energy = %+v", energy)
+	}
+	if err := json.Unmarshal([]byte(`{"type": "Plasma"}`), &energy); err == nil {
+		t.Error("decoding an unknown energy type succeeded")
+	}
+}
+
+func TestNormalizeEnergy(t *testing.T) {
+	card := tcgdex.Card{
+		Types:      []string{"fire"},
+		Attacks:    []tcgdex.Attack{{Name: "Ember", Cost: []string{"R", "Colorless"}}},
+		Weaknesses: []tcgdex.Weakness{{Type: "{W}", Value: "+20"}},
+	}
+	if err := NormalizeEnergy(&card); err != nil {
+		t.Fatal(err)
+	}
+	if card.Types[0] != "Fire" || card.Attacks[0].Cost[0] != "Fire" || card.Attacks[0].Cost[1] != "Colorless" || card.Weaknesses[0].Type != "Water" {
+		t.Errorf("normalized card = %+v", card)
+	}
+
+	card.Attacks[0].Cost = []string{"Plasma"}
+	if err := NormalizeEnergy(&card); err == nil {
+		t.Error("NormalizeEnergy accepted an unknown cost")
+	}
+}
+
+func TestCardEnergyAccessors(t *testing.T) {
+	card := Card{Card: tcgdex.Card{
+		Types: []string{"Fire"},
+		Attacks: []tcgdex.Attack{
+			{Name: "Ember", Cost: []string{"{R}", "Colorless"}},
+			{Name: "Scratch"},
+			{Name: "Plasma Burst", Cost: []string{"Plasma"}},
+		},
+		Weaknesses: []tcgdex.Weakness{{Type: "W", Value: "+20"}},
+	}}
+
+	tests := []struct {
+		name string
+		got  []EnergyType
+		want []EnergyType
+	}{
+		{"PokemonTypes()", card.PokemonTypes(), []EnergyType{EnergyFire}},
+		{"Cost(0)", card.Cost(0), []EnergyType{EnergyFire, EnergyColorless}},
+		{"Cost(1)", card.Cost(1), nil},
+		{"Cost(2)", card.Cost(2), []EnergyType{"Plasma"}},
+		{"WeaknessTypes()", card.WeaknessTypes(), []EnergyType{EnergyWater}},
+		{"WeaknessTypes() of a Trainer", (&Card{}).WeaknessTypes(), nil},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+	if card.Cost(2)[0].Known() {
+		t.Error("an unknown cost reports itself as known")
+	}
+}
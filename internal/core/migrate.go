@@ -0,0 +1,447 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// UnmarshalJSON decodes conditions, upgrading the flat map of keys such as
+// "on_coin_flip" and "target_type" that processed card files held before
+// conditions were typed.
+func (c *Conditions) UnmarshalJSON(data []byte) error {
+	type plain Conditions // Without this method, to avoid recursion
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var conditions plain
+	err := decoder.Decode(&conditions)
+	if err == nil {
+		*c = Conditions(conditions)
+		return nil
+	}
+
+	var legacy map[string]interface{}
+	if json.Unmarshal(data, &legacy) != nil {
+		return err
+	}
+	migrated, migrateErr := MigrateConditions(legacy)
+	if migrateErr != nil {
+		return fmt.Errorf("%w (and as legacy conditions: %v)", err, migrateErr)
+	}
+	*c = *migrated
+	return nil
+}
+
+// UnmarshalJSON decodes an effect, upgrading legacy conditions as
+// Conditions.UnmarshalJSON does. Legacy files wrote "next_turn" for either
+// player's next turn, so where the effect's text names the opponent's next
+// turn, that is the duration it gets.
+func (e *Effect) UnmarshalJSON(data []byte) error {
+	type plain Effect // Without this method, to avoid recursion
+	var effect plain
+	if err := json.Unmarshal(data, &effect); err != nil {
+		return err
+	}
+	*e = Effect(effect)
+
+	var legacy struct {
+		Conditions struct {
+			Duration string `json:"duration"`
+		} `json:"conditions"`
+	}
+	if json.Unmarshal(data, &legacy) == nil && legacy.Conditions.Duration == "next_turn" &&
+		e.Conditions != nil && namesOpponentNextTurn(e.Description) {
+		e.Conditions.Duration = DurationOpponentNextTurn
+	}
+	return nil
+}
+
+// namesOpponentNextTurn reports whether text is about the opponent's next
+// turn rather than the player's own.
+func namesOpponentNextTurn(text string) bool {
+	text = strings.ToLower(strings.ReplaceAll(text, "’", "'"))
+	return strings.Contains(text, "opponent's next turn") && !strings.Contains(text, "during your next turn")
+}
+
+// MigrateConditions converts conditions in the legacy flat layout to typed
+// Conditions. It fails on keys it doesn't know, or on two keys that disagree
+// about the same field.
+func MigrateConditions(legacy map[string]interface{}) (*Conditions, error) {
+	upgraded, err := UpgradeConditionMap(legacy)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(upgraded)
+	if err != nil {
+		return nil, err
+	}
+	type plain Conditions
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var conditions plain
+	if err := decoder.Decode(&conditions); err != nil {
+		return nil, err
+	}
+	c := Conditions(conditions)
+	return &c, nil
+}
+
+// UpgradeConditionMap rewrites legacy flat conditions into the JSON layout of
+// Conditions, without decoding them. Strings starting with "$", such as the
+// expressions in effect rule templates, are carried over as they are, with an
+// "|upper" filter added where values are now upper case.
+func UpgradeConditionMap(legacy map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	var err error
+	set := func(path string, value interface{}) {
+		if err != nil {
+			return
+		}
+		parts := strings.Split(path, ".")
+		m := out
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := m[part].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				m[part] = next
+			}
+			m = next
+		}
+		key := parts[len(parts)-1]
+		if existing, ok := m[key]; ok && !reflect.DeepEqual(existing, value) {
+			err = fmt.Errorf("conditions disagree about %s: %v and %v", path, existing, value)
+			return
+		}
+		m[key] = value
+	}
+
+	trigger, _ := legacy["trigger"].(string)
+	keys := make([]string, 0, len(legacy))
+	for key := range legacy {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value := legacy[key]
+		switch key {
+		case "on_coin_flip", "on":
+			set("coinFlip.result", upper(value))
+		case "chance":
+			set("coinFlip.chance", value)
+		case "num_flips":
+			set("coinFlip.flips", value)
+		case "num_flips_scales_by":
+			set("coinFlip.flipsPer", value)
+
+		case "duration":
+			set("duration", upper(value))
+
+		case "trigger":
+			set("trigger.event", value)
+		case "phase":
+			set("trigger.phase", value)
+		case "status":
+			set("trigger.status", upper(value))
+		case "property":
+			set("trigger.property", value)
+		case "opponent_name", "pokemon_name":
+			set("trigger.name", value)
+		case "opponent_stage":
+			set("trigger.stage", stage(value))
+		case "discarded_type":
+			set("trigger.type", value)
+		case "required_count":
+			set("trigger.count", value)
+		case "attack_name":
+			if trigger == string(TriggerAttackUsedLastTurn) {
+				set("trigger.attackName", value)
+			} else {
+				set("filter.attackName", value)
+			}
+
+		case "requirement":
+			set("requirement.text", value)
+		case "location":
+			set("requirement.location", zone(value))
+		case "requires_in_play":
+			set("requirement.inPlay", list(value))
+		case "requiredEnergyType":
+			set("requirement.energyType", value)
+		case "requiredExtraEnergyCount":
+			set("requirement.extraEnergy", value)
+		case "requires_energy":
+			set("requirement.attackEnergy", value)
+		case "cost_discard_hand":
+			set("requirement.discardFromHand", value)
+		case "not_first_turn":
+			set("requirement.notFirstTurn", value)
+		case "not_played_this_turn":
+			set("requirement.notPlayedThisTurn", value)
+
+		case "source":
+			if legacySourcePools[value] {
+				set("source.pool", value)
+			} else {
+				set("source.zone", zone(value))
+			}
+		case "source_type":
+			set("source.type", value)
+		case "source_subtype":
+			set("source.subtype", value)
+
+		case "destination":
+			set("destination.zone", zone(value))
+		case "destination_type":
+			set("destination.type", value)
+		case "on_match":
+			set("destination.onMatch", value)
+		case "otherwise":
+			set("destination.otherwise", value)
+
+		case "scale_by":
+			set("scaling.by", value)
+		case "scale_by_type":
+			if value == "EVOLUTION" {
+				set("scaling.evolved", true)
+			} else {
+				set("scaling.type", value)
+			}
+		case "scale_by_name", "scale_by_names":
+			set("scaling.names", list(value))
+		case "max_discard":
+			set("scaling.max", value)
+		case "is_base_damage":
+			set("scaling.base", value)
+		case "amount_equals":
+			set("scaling.by", value)
+			set("scaling.equals", true)
+
+		case "target_player":
+			set("filter.player", value)
+		case "target_pool", "target":
+			set("filter.pool", value)
+		case "target_location":
+			set("filter.location", zone(value))
+		case "target_all":
+			set("filter.all", value)
+		case "target_type", "pokemon_type":
+			set("filter.type", value)
+		case "pokemonType":
+			if value != "ANY" { // Any Pokémon is no filter at all
+				set("filter.type", value)
+			}
+		case "target_stage", "target_if_stage":
+			set("filter.stage", stage(value))
+		case "target_subtype":
+			set("filter.subtype", subtype(value))
+		case "target_names", "target_name", "pokemonNames":
+			set("filter.names", list(value))
+		case "exclude_name":
+			set("filter.excludeName", value)
+		case "target_evolves_from", "evolvesFrom":
+			set("filter.evolvesFrom", value)
+		case "evolution_stage":
+			set("filter.evolutionStage", stage(value))
+		case "target_condition":
+			set("filter.condition", value)
+		case "target_energy":
+			if value == "NEXT_GENERATED" {
+				set("energy.next", true)
+			} else {
+				set("filter.energy", value)
+			}
+		case "card_type":
+			set("filter.cardType", value)
+		case "opponent_subtype":
+			set("opponent.subtype", subtype(value))
+		case "from_types":
+			set("attacker.types", list(value))
+		case "from_pokemon_type":
+			set("attacker.subtype", subtype(value))
+
+		case "energyType", "energy_type":
+			set("energy.type", value)
+		case "energyTypes":
+			set("energy.types", list(value))
+		case "possible_types":
+			set("energy.possibleTypes", mapList(value, func(s string) string { return strings.TrimPrefix(s, "or ") }))
+		case "random_energy":
+			set("energy.random", value)
+		case "random_type":
+			set("energy.randomType", value)
+		case "distribute_freely":
+			set("energy.distributeFreely", value)
+
+		case "effect":
+			set("modifier.effect", value)
+		case "restriction":
+			set("modifier.restriction", value)
+		case "prevent":
+			set("modifier.prevent", value)
+		case "amount":
+			if value == "ALL" {
+				set("modifier.all", true)
+			} else {
+				set("modifier.amount", value)
+			}
+		case "cost_amount":
+			set("modifier.costAmount", value)
+		case "cost_type":
+			set("modifier.costType", value)
+		case "statuses":
+			if value == "ALL" {
+				set("modifier.allStatuses", true)
+			} else {
+				set("modifier.statuses", upperList(value))
+			}
+		case "possible_statuses":
+			set("modifier.possibleStatuses", upperList(value))
+		case "hits":
+			set("modifier.hits", value)
+		case "remaining_hp":
+			set("modifier.remainingHP", value)
+		case "draw_until":
+			set("modifier.drawUntil", value)
+		case "random":
+			set("modifier.random", value)
+		case "player_chooses":
+			set("modifier.playerChooses", value)
+		case "voluntary":
+			set("modifier.optional", value)
+		case "reveal":
+			set("modifier.reveal", value)
+		case "reveal_hand":
+			set("modifier.revealHand", value)
+		case "may_shuffle":
+			set("modifier.mayShuffle", value)
+		case "stacking":
+			set("modifier.stacks", value)
+		case "ends_turn":
+			set("modifier.endsTurn", value)
+		case "can_retreat":
+			if value == false {
+				set("modifier.cantRetreat", true)
+			}
+		case "discard_any_time":
+			set("modifier.discardAnyTime", value)
+		case "discard_tool":
+			set("modifier.discardTool", value)
+
+		case "choice":
+			set("choice", value)
+		case "depends_on":
+			set("dependsOn", value)
+
+		default:
+			return nil, fmt.Errorf("unknown legacy condition %q", key)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// legacyZones maps the spellings of places used by legacy conditions.
+var legacyZones = map[string]Zone{
+	"EnergyZone":  ZoneEnergyZone,
+	"DiscardPile": ZoneDiscardPile,
+	"BENCHED":     ZoneBench,
+}
+
+// legacySourcePools are legacy sources that name Pokémon rather than places.
+var legacySourcePools = map[interface{}]bool{
+	"ALL_FRIENDLY":         true,
+	"ANY_FRIENDLY_DAMAGED": true,
+}
+
+func zone(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || isExpression(s) {
+		return upper(value)
+	}
+	if z, ok := legacyZones[s]; ok {
+		return string(z)
+	}
+	return strings.ToUpper(s)
+}
+
+// stage spells stages the way card data does, as in "Basic" or "Stage 2".
+func stage(value interface{}) interface{} {
+	if value == "BASIC" {
+		return "Basic"
+	}
+	return value
+}
+
+func subtype(value interface{}) interface{} {
+	switch value {
+	case "Ultra Beasts":
+		return "Ultra Beast"
+	case "EX":
+		return "ex"
+	}
+	return value
+}
+
+func upper(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if isExpression(s) {
+		if strings.HasSuffix(s, "|upper") {
+			return s
+		}
+		return s + "|upper"
+	}
+	return strings.ToUpper(s)
+}
+
+// list wraps a single value in a list. An expression that splits its value
+// already gives a list.
+func list(value interface{}) interface{} {
+	if s, ok := value.(string); ok && !(isExpression(s) && strings.Contains(s, "|split:")) {
+		return []interface{}{s}
+	}
+	return value
+}
+
+func upperList(value interface{}) interface{} {
+	if s, ok := value.(string); ok && isExpression(s) {
+		return upper(s)
+	}
+	return mapList(value, strings.ToUpper)
+}
+
+func mapList(value interface{}, f func(string) string) interface{} {
+	switch value := value.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, v := range value {
+			if s, ok := v.(string); ok && !isExpression(s) {
+				v = f(s)
+			}
+			out[i] = v
+		}
+		return out
+	case []string:
+		out := make([]interface{}, len(value))
+		for i, s := range value {
+			out[i] = f(s)
+		}
+		return out
+	case string:
+		if !isExpression(value) {
+			return []interface{}{f(value)}
+		}
+	}
+	return list(value)
+}
+
+func isExpression(s string) bool {
+	return strings.HasPrefix(s, "$")
+}
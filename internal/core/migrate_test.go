@@ -0,0 +1,83 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalLegacyConditions(t *testing.T) {
+	var effect Effect
+	err := json.Unmarshal([]byte(`{"type": "ATTACH_ENERGY", "conditions": {
+		"on_coin_flip": "heads", "duration": "this_turn", "source": "EnergyZone",
+		"target_type": "W", "target_stage": "BASIC", "energyType": "W", "amount": "ALL",
+		"statuses": ["asleep"], "can_retreat": false, "depends_on": 0}}`), &effect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dependsOn := 0
+	want := &Conditions{
+		CoinFlip: &CoinFlip{Result: CoinHeads},
+		Duration: DurationThisTurn,
+		Source:   &Source{Zone: ZoneEnergyZone},
+		Filter:   &Filter{Type: EnergyWater, Stage: "Basic"},
+		Energy:   &Energy{Type: EnergyWater},
+		Modifier: &Modifier{
+			All:         true,
+			Statuses:    []StatusCondition{StatusAsleep},
+			CantRetreat: true,
+		},
+		DependsOn: &dependsOn,
+	}
+	if !reflect.DeepEqual(effect.Conditions, want) {
+		got, _ := json.Marshal(effect.Conditions)
+		t.Errorf("conditions = %s", got)
+	}
+
+	// Typed conditions decode as they are.
+	data, err := json.Marshal(effect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again Effect
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, effect) {
+		t.Errorf("round trip = %+v, want %+v", again, effect)
+	}
+}
+
+func TestUnmarshalLegacyNextTurn(t *testing.T) {
+	tests := []struct {
+		description string
+		want        Duration
+	}{
+		{"During your opponent’s next turn, attacks used by the Defending Pokémon do −20 damage.", DurationOpponentNextTurn},
+		{"During your next turn, this Pokémon can't attack.", DurationNextTurn},
+	}
+	for _, tt := range tests {
+		data, _ := json.Marshal(map[string]interface{}{
+			"type": "APPLY_RESTRICTION", "description": tt.description, "conditions": map[string]interface{}{"duration": "next_turn"},
+		})
+		var effect Effect
+		if err := json.Unmarshal(data, &effect); err != nil {
+			t.Fatal(err)
+		}
+		if effect.Conditions.Duration != tt.want {
+			t.Errorf("%q lasts %s, want %s", tt.description, effect.Conditions.Duration, tt.want)
+		}
+	}
+}
+
+func TestMigrateConditionsRejects(t *testing.T) {
+	for _, legacy := range []map[string]interface{}{
+		{"no_such_condition": true},
+		{"target_type": "W", "pokemon_type": "R"},
+		{"num_flips": "three"},
+	} {
+		if c, err := MigrateConditions(legacy); err == nil {
+			t.Errorf("MigrateConditions(%v) = %+v, want an error", legacy, c)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SchemaID identifies the JSON schema of an Effect.
+const SchemaID = "https://github.com/cpritch/genomon/effect.schema.json"
+
+// schemaEnums lists the values of the string types whose values are fixed by
+// the simulator rather than by card text.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(CoinResult("")): {
+		string(CoinHeads), string(CoinTails), string(CoinDoubleHeads),
+	},
+	reflect.TypeOf(Duration("")): {
+		string(DurationThisTurn), string(DurationNextTurn), string(DurationOpponentNextTurn),
+		string(DurationFirstTurn), string(DurationPersistent), string(DurationPersistentActive),
+	},
+	reflect.TypeOf(TriggerEvent("")): {
+		string(TriggerOncePerTurn), string(TriggerAsOftenAsYouLike), string(TriggerEndOfTurn),
+		string(TriggerEndOfFirstTurn), string(TriggerEndOfOpponentNextTurn), string(TriggerKnockedOut),
+		string(TriggerOnEvolve), string(TriggerOnPlayToBench), string(TriggerAttachEnergyToSelf),
+		string(TriggerAttackUsedLastTurn), string(TriggerDamagedLastTurn),
+		string(TriggerFriendlyKOLastTurn), string(TriggerEvolvedThisTurn),
+		string(TriggerSwitchedInThisTurn), string(TriggerPlayedSupporterThisTurn),
+		string(TriggerDiscardedCardIsType), string(TriggerDifferentEnergyTypesAttached),
+		string(TriggerHasEnergyAttached), string(TriggerSelfHasTypedEnergy),
+		string(TriggerSelfHasDamage), string(TriggerSelfHasNoDamage), string(TriggerSelfHasTool),
+		string(TriggerAnyBenchedFriendlyHasDamage), string(TriggerPokemonOnBench),
+		string(TriggerOpponentHasAbility), string(TriggerOpponentHasDamage),
+		string(TriggerOpponentHasProperty), string(TriggerOpponentHasSpecialCondition),
+		string(TriggerOpponentHasStatus), string(TriggerOpponentHasTool),
+		string(TriggerOpponentHPGreater), string(TriggerOpponentIsEvolved), string(TriggerOpponentIsEX),
+		string(TriggerOpponentIsName), string(TriggerOpponentIsStage), string(TriggerOpponentKO),
+	},
+	reflect.TypeOf(Zone("")): {
+		string(ZoneActive), string(ZoneBench), string(ZoneHand), string(ZoneDeck),
+		string(ZoneDiscardPile), string(ZoneEnergyZone), string(ZoneSelf),
+	},
+	reflect.TypeOf(EnergyType("")): energyNames(EnergyTypes),
+	reflect.TypeOf(StatusCondition("")): {
+		string(StatusPoisoned), string(StatusConfused), string(StatusAsleep),
+		string(StatusBurned), string(StatusParalyzed),
+	},
+}
+
+// EffectSchema returns a JSON schema (draft 2020-12) describing Effect as it
+// is written to processed card files, so that tools outside Go can check and
+// consume parsed effects.
+func EffectSchema() ([]byte, error) {
+	defs := make(map[string]interface{})
+	schema := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     SchemaID,
+		"title":   "Effect",
+		"$ref":    "#/$defs/Effect",
+		"$defs":   defs,
+	}
+	typeSchema(reflect.TypeOf(Effect{}), defs)
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// typeSchema returns the schema of t, adding the structs it uses to defs.
+func typeSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+		if _, ok := defs[t.Name()]; ok {
+			return ref
+		}
+		properties := make(map[string]interface{})
+		var required []string
+		def := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		defs[t.Name()] = def // Before the fields, in case a field refers back to t
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = typeSchema(field.Type, defs)
+			if !strings.Contains(options, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		if required != nil {
+			def["required"] = required
+		}
+		return ref
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), defs)}
+	case reflect.String:
+		schema := map[string]interface{}{"type": "string"}
+		if values, ok := schemaEnums[t]; ok {
+			schema["enum"] = values
+		}
+		return schema
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	panic("core: no JSON schema for " + t.String())
+}
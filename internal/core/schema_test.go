@@ -0,0 +1,35 @@
+package core
+
+import (
+	"flag"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite effect.schema.json from the Go types")
+
+const schemaFile = "../../effect.schema.json"
+
+// TestEffectSchemaUpToDate checks that effect.schema.json describes the
+// current Effect types. After changing them, regenerate it with:
+//
+//	go test ./internal/core -run Schema -update
+func TestEffectSchemaUpToDate(t *testing.T) {
+	schema, err := EffectSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.WriteFile(schemaFile, schema, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	data, err := os.ReadFile(schemaFile)
+	if err != nil {
+		t.Fatalf("%v; run with -update to create it", err)
+	}
+	if string(data) != string(schema) {
+		t.Errorf("%s is out of date; run: go test ./internal/core -run Schema -update", schemaFile)
+	}
+}
@@ -0,0 +1,101 @@
+package effects
+
+import (
+	"fmt"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// ParseCard parses the effects of a card's abilities and attacks, and the text
+// of Trainer cards. It also returns an error for each attack whose parsed
+// effects contradict its printed damage, and a *PartialError for each ability
+// or attack whose text was only partly parsed; the card is still fully parsed
+// in either case.
+func ParseCard(card tcgdex.Card) (core.Card, []error) {
+	parsedCard := core.Card{
+		Card:            card,
+		ParsedAbilities: []core.Effect{},
+		ParsedAttacks:   []core.Effect{},
+	}
+
+	var problems []error
+	for _, ability := range card.Abilities {
+		parsed, err := parseNamed(ability.Name, ability.Effect)
+		if err != nil {
+			problems = append(problems, err)
+		}
+		parsedCard.ParsedAbilities = append(parsedCard.ParsedAbilities, parsed...)
+	}
+
+	for _, attack := range card.Attacks {
+		parsed, err := parseNamed(attack.Name, attack.Effect)
+		if err != nil {
+			problems = append(problems, err)
+		}
+		if err := checkDamage(attack, parsed); err != nil {
+			problems = append(problems, err)
+		}
+		parsedCard.ParsedAttacks = append(parsedCard.ParsedAttacks, parsed...)
+	}
+
+	if card.IsTrainer() && card.Text != "" {
+		for _, effect := range ParseTrainer(card.Text) {
+			effect.Name = card.Name // Trainer effects are named after the card
+			parsedCard.ParsedTrainerEffects = append(parsedCard.ParsedTrainerEffects, effect)
+		}
+	}
+
+	return parsedCard, problems
+}
+
+// ParseAttack parses an attack's effect text, naming each effect after the
+// attack. The parsed effects are always returned; a non-nil error means they
+// disagree with the attack's printed damage, which usually points at a
+// misparse.
+func ParseAttack(attack tcgdex.Attack) ([]core.Effect, error) {
+	parsed, _ := parseNamed(attack.Name, attack.Effect)
+	return parsed, checkDamage(attack, parsed)
+}
+
+// parseNamed parses the effect text of the attack or ability called name,
+// naming each effect after it. The error is a *PartialError if the text was
+// only partly parsed.
+func parseNamed(name, text string) ([]core.Effect, error) {
+	if text == "" {
+		return nil, nil
+	}
+	result := ParseText(text)
+	for i := range result.Effects {
+		result.Effects[i].Name = name // Add name for mapping
+	}
+	if result.Partial() {
+		return result.Effects, &PartialError{Name: name, Unparsed: result.Unparsed}
+	}
+	return result.Effects, nil
+}
+
+// checkDamage verifies that attacks printed with a "+" or "×" damage have an
+// effect that modifies their damage, and that "×" attacks scale by the
+// printed base amount.
+func checkDamage(attack tcgdex.Attack, parsed []core.Effect) error {
+	modifier := attack.Damage.Modifier
+	if modifier != tcgdex.DamagePlus && modifier != tcgdex.DamageTimes {
+		return nil
+	}
+
+	modified := false
+	for _, effect := range parsed {
+		if !effect.Type.ModifiesDamage() {
+			continue
+		}
+		modified = true
+		if modifier == tcgdex.DamageTimes && effect.Type == core.EffectScalingDamage && effect.Amount != attack.Damage.Base {
+			return fmt.Errorf("attack %q prints %s damage but its effect scales by %d", attack.Name, attack.Damage, effect.Amount)
+		}
+	}
+	if !modified {
+		return fmt.Errorf("attack %q prints %s damage but no parsed effect modifies it", attack.Name, attack.Damage)
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package effects
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+// Clause is one step of an effect text: usually a sentence, together with
+// any sentences that only qualify it, such as "This effect doesn't stack.",
+// reminder text in parentheses, or a prerequisite like "You can use this
+// attack only if ...".
+type Clause struct {
+	Text string
+	// DependsOn is the index of the clause this one is bound to, such as the
+	// coin flip an "If heads, ..." clause reports on, or -1 if it stands
+	// alone.
+	DependsOn int
+}
+
+var (
+	// Sentences that qualify the next one rather than adding an effect.
+	clausePrerequisiteRegex = regexp.MustCompile(`^(?:You can use this (?:attack|card) only if|This Ability works if|You must .+ in order to use this)\b`)
+	// Sentences that qualify the previous one rather than adding an effect.
+	clauseModifierRegex = regexp.MustCompile(`^(?:\(|This effect\b)|\binstead of the usual\b|\bwill not be chosen\b`)
+	// Sentences that report on an earlier coin flip or random choice.
+	clauseCoinResultRegex = regexp.MustCompile(`^(?:If (?:heads|tails|both|either|at least|all of them|any of them|none of them)|For each (?:heads|time))\b`)
+	clauseCoinFlipRegex   = regexp.MustCompile(`(?i)\bflips? (?:a coin|\d+ coins)|\bchosen at random\b`)
+	// Sentences that only make sense straight after the previous one.
+	clauseFollowUpRegex = regexp.MustCompile(`^(?:If you (?:do|did|don't|use this)|If that\b|For each of those\b|Do \d+ damage to it\b|Heal \d+ damage from it\b|Your opponent reveals that\b)`)
+)
+
+// SplitClauses splits effect text into clauses, binding clauses that report
+// on a coin flip to the flip and follow-ups to the clause before them.
+func SplitClauses(text string) []Clause {
+	var clauses []Clause
+	prerequisite := ""
+	for _, sentence := range splitSentences(text) {
+		last := len(clauses) - 1
+		switch {
+		case clausePrerequisiteRegex.MatchString(sentence):
+			prerequisite += sentence + " "
+			continue
+		case last >= 0 && clauseModifierRegex.MatchString(sentence):
+			clauses[last].Text += " " + sentence
+		case last >= 0 && clauseCoinResultRegex.MatchString(sentence):
+			flip := last
+			for i := last; i >= 0; i-- {
+				if clauseCoinFlipRegex.MatchString(clauses[i].Text) {
+					flip = i
+					break
+				}
+			}
+			clauses = append(clauses, Clause{Text: sentence, DependsOn: flip})
+		case last >= 0 && clauseFollowUpRegex.MatchString(sentence):
+			clauses = append(clauses, Clause{Text: sentence, DependsOn: last})
+		default:
+			clauses = append(clauses, Clause{Text: prerequisite + sentence, DependsOn: -1})
+			prerequisite = ""
+		}
+	}
+	if prerequisite != "" {
+		clauses = append(clauses, Clause{Text: strings.TrimSpace(prerequisite), DependsOn: -1})
+	}
+	return clauses
+}
+
+// splitSentences splits text after each full stop that is followed by the
+// start of a new sentence, leaving text in parentheses whole.
+func splitSentences(text string) []string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	var sentences []string
+	start, depth := 0, 0
+	for i, r := range runes {
+		switch r {
+		case '(':
+			depth++
+			continue
+		case ')':
+			depth = max(depth-1, 0)
+			if i == 0 || runes[i-1] != '.' && runes[i-1] != '!' {
+				continue
+			}
+		case '.', '!':
+		default:
+			continue
+		}
+		if depth > 0 || i+2 >= len(runes) || runes[i+1] != ' ' {
+			continue
+		}
+		if next := runes[i+2]; !unicode.IsUpper(next) && !unicode.IsDigit(next) && next != '(' {
+			continue
+		}
+		if strings.HasSuffix(string(runes[start:i]), "Mr") {
+			continue
+		}
+		sentences = append(sentences, string(runes[start:i+1]))
+		start = i + 2
+	}
+	if start < len(runes) {
+		sentences = append(sentences, string(runes[start:]))
+	}
+	return sentences
+}
+
+// Parsed is the result of parsing an effect text.
+type Parsed struct {
+	Effects []core.Effect
+	// Unparsed lists the clauses that no effect accounts for: those no rule
+	// matches, and those ignored by a rule that matched the clauses beside
+	// them.
+	Unparsed []string
+}
+
+// Partial reports whether some, but not all, of the text was understood.
+func (p Parsed) Partial() bool {
+	if len(p.Unparsed) == 0 {
+		return false
+	}
+	for _, effect := range p.Effects {
+		if effect.Type != core.EffectUnknown {
+			return true
+		}
+	}
+	return false
+}
+
+// PartialError reports an effect text that was only partly parsed.
+type PartialError struct {
+	Name     string   // The attack or ability the text belongs to
+	Unparsed []string // Clauses no parsed effect accounts for
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%q was only partly parsed; unparsed: %q", e.Name, e.Unparsed)
+}
+
+// Parse takes the raw text of an effect and turns it into structured Effect
+// objects, one or more per clause, in the order the text gives them.
+func Parse(text string) []core.Effect {
+	return ParseText(text).Effects
+}
+
+// ParseText parses effect text like Parse, also reporting the clauses it
+// couldn't account for.
+//
+// Many rules match several clauses at once ("Flip a coin. If heads, ..."),
+// and any rule may match just part of the text it's given, so the clauses
+// are grouped in whichever way leaves the fewest clauses unaccounted for,
+// preferring fewer, larger groups. A text that one rule fully covers is
+// therefore parsed exactly as a single clause would be. A clause parsed apart
+// from the coin flip or clause it depends on is parsed with that clause in
+// front of it, and its effects record the index of the first effect parsed
+// from that clause as the "depends_on" condition.
+//
+// The text is parsed once normalised with Normalize, but the descriptions
+// of the effects and the unparsed clauses are given as the original text.
+func ParseText(text string) Parsed {
+	return parseOriginalText(text, recordProvenance)
+}
+
+// parseOriginalText parses text for ParseText, with provenance if
+// withProvenance is set.
+func parseOriginalText(text string, withProvenance bool) Parsed {
+	n := Normalize(text)
+	result := parseText(n.Text)
+	for i := range result.Effects {
+		result.Effects[i].Description = n.Restore(result.Effects[i].Description)
+	}
+	for i, clause := range result.Unparsed {
+		result.Unparsed[i] = n.Restore(clause)
+	}
+	n.attribute(result.Effects, withProvenance)
+	return result
+}
+
+// parseText parses normalised text for ParseText.
+func parseText(text string) Parsed {
+	clauses := SplitClauses(text)
+	if len(clauses) <= 1 {
+		parsed := parseClause(text)
+		if isUnknown(parsed) {
+			return Parsed{Effects: parsed, Unparsed: []string{text}}
+		}
+		return Parsed{Effects: parsed}
+	}
+
+	p := &compoundParser{text: text, clauses: clauses, cache: make(map[string][]core.Effect)}
+	n := len(clauses)
+
+	// best[i] is the best grouping of clauses[i:], built back to front.
+	type choice struct {
+		group       *clauseGroup
+		cost, count int
+	}
+	best := make([]choice, n+1)
+	for i := n - 1; i >= 0; i-- {
+		best[i] = choice{cost: -1}
+		for j := n; j > i; j-- {
+			group := p.group(i, j)
+			if group == nil {
+				continue
+			}
+			c := choice{group: group, cost: len(group.unparsed) + best[j].cost, count: 1 + best[j].count}
+			if best[i].cost < 0 || c.cost < best[i].cost || c.cost == best[i].cost && c.count < best[i].count {
+				best[i] = c
+			}
+		}
+	}
+
+	var result Parsed
+	firstEffect := make(map[int]int, n) // Clause index to the index of its group's first effect
+	for i := 0; i < n; i = best[i].group.end {
+		group := best[i].group
+		for k := group.start; k < group.end; k++ {
+			firstEffect[k] = len(result.Effects)
+		}
+		for _, effect := range group.effects {
+			if group.context >= 0 {
+				conditions := *withConditions(effect.Conditions)
+				dependsOn := firstEffect[group.context]
+				conditions.DependsOn = &dependsOn
+				effect.Conditions = &conditions
+			}
+			result.Effects = append(result.Effects, effect)
+		}
+		result.Unparsed = append(result.Unparsed, group.unparsed...)
+	}
+	return result
+}
+
+// compoundParser parses groups of clauses from one effect text.
+type compoundParser struct {
+	text    string
+	clauses []Clause
+	cache   map[string][]core.Effect
+}
+
+// clauseGroup is a run of clauses parsed together.
+type clauseGroup struct {
+	start, end int // The clauses covered, as a half-open range
+	context    int // The clause parsed in front of the group, or -1
+	effects    []core.Effect
+	unparsed   []string
+}
+
+// group parses clauses[start:end] together, or returns nil if the group would
+// split a clause from one it depends on.
+func (p *compoundParser) group(start, end int) *clauseGroup {
+	g := &clauseGroup{start: start, end: end, context: -1}
+	if dep := p.clauses[start].DependsOn; dep >= 0 && dep < start {
+		g.context = dep
+	}
+	for k := start + 1; k < end; k++ {
+		if dep := p.clauses[k].DependsOn; dep >= 0 && dep < start && dep != g.context {
+			return nil
+		}
+	}
+
+	text := p.join(start, end)
+	if g.context >= 0 {
+		text = p.clauses[g.context].Text + " " + text
+	}
+	parsed := p.parse(text)
+
+	// If the group parses no differently from the clause in front of it,
+	// nothing in the group was understood.
+	if isUnknown(parsed) || g.context >= 0 && sameEffects(parsed, p.parse(p.clauses[g.context].Text)) {
+		g.effects = []core.Effect{{Type: core.EffectUnknown, Description: p.join(start, end)}}
+		for k := start; k < end; k++ {
+			g.unparsed = append(g.unparsed, p.clauses[k].Text)
+		}
+		return g
+	}
+
+	// A clause at either end of the group that makes no difference to the
+	// result was ignored by the rule that matched.
+	if end-start > 1 {
+		withoutLast := p.join(start, end-1)
+		if g.context >= 0 {
+			withoutLast = p.clauses[g.context].Text + " " + withoutLast
+		}
+		if sameEffects(parsed, p.parse(withoutLast)) {
+			g.unparsed = append(g.unparsed, p.clauses[end-1].Text)
+		}
+		// A clause that later ones depend on, like a coin flip, is implied by
+		// them and may legitimately make no difference.
+		if p.clauses[start+1].DependsOn != start && sameEffects(parsed, p.parse(p.join(start+1, end))) {
+			g.unparsed = append(g.unparsed, p.clauses[start].Text)
+		}
+	}
+
+	g.effects = parsed
+	return g
+}
+
+// join returns the text of clauses[start:end], which for all of them is the
+// original text.
+func (p *compoundParser) join(start, end int) string {
+	if start == 0 && end == len(p.clauses) {
+		return p.text
+	}
+	texts := make([]string, 0, end-start)
+	for _, clause := range p.clauses[start:end] {
+		texts = append(texts, clause.Text)
+	}
+	return strings.Join(texts, " ")
+}
+
+// parse runs the rules over text, remembering the result since the same
+// clauses are tried in many groupings.
+func (p *compoundParser) parse(text string) []core.Effect {
+	if parsed, ok := p.cache[text]; ok {
+		return parsed
+	}
+	parsed := parseClause(text)
+	p.cache[text] = parsed
+	return parsed
+}
+
+// isUnknown reports whether parsed is the result of no rule matching.
+func isUnknown(parsed []core.Effect) bool {
+	return len(parsed) == 1 && parsed[0].Type == core.EffectUnknown
+}
+
+// sameEffects compares two parses, ignoring the text each was parsed from
+// and the rules that parsed it.
+func sameEffects(a, b []core.Effect) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := a[i], b[i]
+		x.Description, y.Description = "", ""
+		x.Provenance, y.Provenance = nil, nil
+		if !reflect.DeepEqual(x, y) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package effects
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+func TestSplitClauses(t *testing.T) {
+	text := "You can use this attack only if you have Uxie and Azelf on your Bench. Flip a coin. " +
+		"If heads, this attack does 40 more damage. Switch out your opponent's Active Pokémon to the Bench. " +
+		"(Your opponent chooses the new Active Pokémon.) If tails, this Pokémon also does 20 damage to itself."
+	want := []Clause{
+		{Text: "You can use this attack only if you have Uxie and Azelf on your Bench. Flip a coin.", DependsOn: -1},
+		{Text: "If heads, this attack does 40 more damage.", DependsOn: 0},
+		{Text: "Switch out your opponent's Active Pokémon to the Bench. (Your opponent chooses the new Active Pokémon.)", DependsOn: -1},
+		{Text: "If tails, this Pokémon also does 20 damage to itself.", DependsOn: 0},
+	}
+	if got := SplitClauses(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitClauses =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseTextCompound(t *testing.T) {
+	tests := []struct {
+		text     string
+		types    []core.EffectType
+		unparsed int
+	}{
+		// One rule covers the whole text, so it parses as it always has.
+		{"Flip a coin. If heads, your opponent's Active Pokémon is now Paralyzed.", []core.EffectType{core.EffectApplyStatus}, 0},
+		// Each sentence is its own effect.
+		{"Discard 2 {R} Energy from this Pokémon. This attack does 80 damage to 1 of your opponent's Pokémon.",
+			[]core.EffectType{core.EffectDiscardEnergy, core.EffectSnipeDamage}, 0},
+		// The tails clause is parsed apart from the heads clause, but bound to the same flip.
+		{"Flip a coin. If heads, this attack does 40 more damage. If tails, this Pokémon also does 20 damage to itself.",
+			[]core.EffectType{core.EffectConditionalDamage, core.EffectRecoilDamage}, 0},
+		{"Flip a coin. If heads, your opponent's Active Pokémon is now Paralyzed. If tails, your opponent's Active Pokémon is now Confused.",
+			[]core.EffectType{core.EffectApplyStatus, core.EffectApplyStatus}, 0},
+		// A result clause after another sentence is still bound to the flip.
+		{"Flip 4 coins. This attack does 40 damage for each heads. If at least 2 of them are heads, your opponent's Active Pokémon is now Poisoned.",
+			[]core.EffectType{core.EffectScalingDamage, core.EffectApplyStatus}, 0},
+		{"Discard all Energy attached to this Pokémon. Your opponent's Active Pokémon is now Paralyzed.",
+			[]core.EffectType{core.EffectDiscardEnergy, core.EffectApplyStatus}, 0},
+		// "it" is the Pokémon the sentence before names.
+		{"This Pokémon is now Asleep. Heal 30 damage from it.", []core.EffectType{core.EffectApplyStatus, core.EffectHeal}, 0},
+		// A clause nothing matches is reported rather than dropped.
+		{"This Pokémon is now Asleep. Your opponent sings it a lullaby.", []core.EffectType{core.EffectApplyStatus}, 1},
+	}
+	for _, tt := range tests {
+		result := ParseText(tt.text)
+		var types []core.EffectType
+		for _, effect := range result.Effects {
+			types = append(types, effect.Type)
+		}
+		if !reflect.DeepEqual(types, tt.types) || len(result.Unparsed) != tt.unparsed {
+			t.Errorf("ParseText(%q) = %v, unparsed %q; want %v with %d unparsed", tt.text, types, result.Unparsed, tt.types, tt.unparsed)
+		}
+	}
+
+	for _, text := range []string{
+		"Flip a coin. If heads, this attack does 40 more damage. If tails, this Pokémon also does 20 damage to itself.",
+		"Flip a coin. If heads, your opponent's Active Pokémon is now Paralyzed. If tails, your opponent's Active Pokémon is now Confused.",
+		"Flip 2 coins. This attack does 70 damage for each heads. If at least 1 of them is heads, your opponent's Active Pokémon is now Burned.",
+	} {
+		second := ParseText(text).Effects[1]
+		if second.Conditions == nil || second.Conditions.DependsOn == nil || *second.Conditions.DependsOn != 0 {
+			t.Errorf("ParseText(%q) second effect conditions = %+v, want dependsOn 0", text, second.Conditions)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package effects
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// Coverage reports how much of the effect text in a card pool the parser
+// understands, overall and broken down by set, category and rarity, with
+// the text it doesn't understand grouped into clusters of similar wording.
+type Coverage struct {
+	CoverageStats
+	BySet      []CoverageGroup `json:"bySet"`
+	ByCategory []CoverageGroup `json:"byCategory"` // Abilities, attacks and each kind of Trainer card
+	ByRarity   []CoverageGroup `json:"byRarity"`
+	// Clusters groups the unparsed texts and clauses by similar wording,
+	// largest first, so that one new rule can often cover a whole cluster.
+	Clusters []UnknownCluster `json:"clusters"`
+}
+
+// CoverageStats counts effect texts by how well they parse. A text printed
+// on several cards counts once for each card.
+type CoverageStats struct {
+	Texts    int     `json:"texts"`
+	Parsed   int     `json:"parsed"`   // Fully parsed
+	Partial  int     `json:"partial"`  // Parsed, except for some clauses
+	Unknown  int     `json:"unknown"`  // Not parsed at all
+	Coverage float64 `json:"coverage"` // The percentage of Texts fully parsed
+}
+
+// CoverageGroup is the coverage of one set, category or rarity.
+type CoverageGroup struct {
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"` // The set's name, for sets
+	CoverageStats
+}
+
+// UnknownCluster is a group of unparsed texts with similar wording.
+type UnknownCluster struct {
+	// Shape is the wording of the cluster's most common text, with numbers
+	// written as "#" and energy symbols as "{*}".
+	Shape string        `json:"shape"`
+	Cards int           `json:"cards"` // The number of cards the texts appear on
+	Texts []UnknownText `json:"texts"`
+}
+
+// UnknownText is an unparsed text or clause, and the cards it appears on.
+type UnknownText struct {
+	Text  string   `json:"text"`
+	Cards []string `json:"cards"` // Card IDs
+}
+
+// coverageCategories and coverageRarities are the orders categories and
+// rarities are listed in; any others follow in the order they were found.
+var (
+	coverageCategories = []string{"Ability", "Attack", "Item", "Supporter", "Tool", "Fossil"}
+	coverageRarities   = []string{
+		"One Diamond", "Two Diamond", "Three Diamond", "Four Diamond",
+		"One Star", "Two Star", "Three Star", "One Shiny", "Two Shiny", "Crown", "None",
+	}
+)
+
+// clusterSimilarity is how alike, as the share of words they have in
+// common, an unknown text's shape must be to a cluster's to join it.
+const clusterSimilarity = 0.6
+
+// MeasureCoverage parses every ability, attack and Trainer card text in
+// cards and reports how much of it parses.
+func MeasureCoverage(cards []tcgdex.Card) *Coverage {
+	coverage := &Coverage{}
+	add := func(list *[]CoverageGroup, key, name string, parsed, partial bool) {
+		index := slices.IndexFunc(*list, func(group CoverageGroup) bool { return group.Key == key })
+		if index < 0 {
+			*list = append(*list, CoverageGroup{Key: key, Name: name})
+			index = len(*list) - 1
+		}
+		(*list)[index].count(parsed, partial)
+	}
+
+	unknown := make(map[string][]string) // Unparsed text to card IDs
+	var order []string
+	record := func(card tcgdex.Card, category, text string, result Parsed) {
+		parsed := len(result.Unparsed) == 0 && !isUnknown(result.Effects)
+		partial := result.Partial()
+		unparsed := result.Unparsed
+		if !parsed && !partial {
+			unparsed = []string{text}
+		}
+
+		coverage.count(parsed, partial)
+		add(&coverage.BySet, card.Set.ID, card.Set.Name, parsed, partial)
+		add(&coverage.ByCategory, category, "", parsed, partial)
+		rarity := card.Rarity
+		if rarity == "" {
+			rarity = "None"
+		}
+		add(&coverage.ByRarity, rarity, "", parsed, partial)
+
+		for _, clause := range unparsed {
+			if _, seen := unknown[clause]; !seen {
+				order = append(order, clause)
+			}
+			unknown[clause] = append(unknown[clause], card.ID)
+		}
+	}
+
+	for _, card := range cards {
+		for _, ability := range card.Abilities {
+			if ability.Effect != "" {
+				record(card, "Ability", ability.Effect, ParseText(ability.Effect))
+			}
+		}
+		for _, attack := range card.Attacks {
+			if attack.Effect != "" {
+				record(card, "Attack", attack.Effect, ParseText(attack.Effect))
+			}
+		}
+		if card.IsTrainer() && card.Text != "" {
+			category := string(card.TrainerKind())
+			if category == "" {
+				category = "Trainer"
+			}
+			record(card, category, card.Text, Parsed{Effects: ParseTrainer(card.Text)})
+		}
+	}
+
+	rank := func(order []string) func(a, b CoverageGroup) int {
+		return func(a, b CoverageGroup) int {
+			i, j := slices.Index(order, a.Key), slices.Index(order, b.Key)
+			if i < 0 {
+				i = len(order)
+			}
+			if j < 0 {
+				j = len(order)
+			}
+			return i - j
+		}
+	}
+	slices.SortStableFunc(coverage.ByCategory, rank(coverageCategories))
+	slices.SortStableFunc(coverage.ByRarity, rank(coverageRarities))
+
+	coverage.finish()
+	for _, list := range [][]CoverageGroup{coverage.BySet, coverage.ByCategory, coverage.ByRarity} {
+		for i := range list {
+			list[i].finish()
+		}
+	}
+	coverage.Clusters = clusterUnknown(order, unknown)
+	return coverage
+}
+
+// count adds one text, fully parsed, partly parsed or not at all.
+func (s *CoverageStats) count(parsed, partial bool) {
+	s.Texts++
+	switch {
+	case parsed:
+		s.Parsed++
+	case partial:
+		s.Partial++
+	default:
+		s.Unknown++
+	}
+}
+
+func (s *CoverageStats) finish() {
+	s.Coverage = 100
+	if s.Texts > 0 {
+		s.Coverage = float64(s.Parsed) * 100 / float64(s.Texts)
+	}
+}
+
+// Complete reports whether every text is fully parsed.
+func (s *CoverageStats) Complete() bool {
+	return s.Partial == 0 && s.Unknown == 0
+}
+
+var (
+	shapeNumberRegex = regexp.MustCompile(`[−+]?\d+`)
+	shapeEnergyRegex = regexp.MustCompile(`\{[A-Z]\}`)
+)
+
+// shape returns the wording of text, with the numbers and energy types that
+// tend to vary between otherwise identical effects replaced.
+func shape(text string) string {
+	s := Normalize(text).Text
+	s = shapeEnergyRegex.ReplaceAllString(s, "{*}")
+	return shapeNumberRegex.ReplaceAllString(s, "#")
+}
+
+// clusterUnknown groups texts, given in the order they were found, by
+// similar wording. Each text joins the most similar cluster so far whose
+// first text shares enough of its words, or starts a cluster of its own;
+// texts on the most cards go first, so they lead their clusters.
+func clusterUnknown(texts []string, cards map[string][]string) []UnknownCluster {
+	sorted := append([]string(nil), texts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(cards[sorted[i]]) > len(cards[sorted[j]])
+	})
+
+	var clusters []UnknownCluster
+	var leaders []map[string]bool // The words of each cluster's first text
+	for _, text := range sorted {
+		words := wordSet(shape(text))
+		best, bestSimilarity := -1, clusterSimilarity
+		for i, leader := range leaders {
+			if similarity := jaccard(words, leader); similarity >= bestSimilarity {
+				best, bestSimilarity = i, similarity
+			}
+		}
+		if best < 0 {
+			clusters = append(clusters, UnknownCluster{Shape: shape(text)})
+			leaders = append(leaders, words)
+			best = len(clusters) - 1
+		}
+		clusters[best].Texts = append(clusters[best].Texts, UnknownText{Text: text, Cards: cards[text]})
+		clusters[best].Cards += len(cards[text])
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Cards > clusters[j].Cards
+	})
+	return clusters
+}
+
+func wordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		words[strings.Trim(word, ".,:;!?()")] = true
+	}
+	return words
+}
+
+// jaccard returns the share of the words in either set that are in both.
+func jaccard(a, b map[string]bool) float64 {
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	if union := len(a) + len(b) - shared; union > 0 {
+		return float64(shared) / float64(union)
+	}
+	return 1
+}
+
+// Markdown renders the report as a Markdown document.
+func (c *Coverage) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Effect parser coverage\n\n")
+	fmt.Fprintf(&b, "%d of %d effect texts (%.1f%%) are fully parsed, %d are partly parsed and %d are not parsed at all.\n",
+		c.Parsed, c.Texts, c.Coverage, c.Partial, c.Unknown)
+
+	table := func(title, column string, groups []CoverageGroup) {
+		fmt.Fprintf(&b, "\n## By %s\n\n", title)
+		fmt.Fprintf(&b, "| %s | Texts | Parsed | Partial | Unknown | Coverage |\n", column)
+		b.WriteString("| --- | ---: | ---: | ---: | ---: | ---: |\n")
+		for _, group := range groups {
+			label := group.Key
+			if group.Name != "" {
+				label = fmt.Sprintf("%s (%s)", group.Name, group.Key)
+			}
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %.1f%% |\n",
+				label, group.Texts, group.Parsed, group.Partial, group.Unknown, group.Coverage)
+		}
+	}
+	table("set", "Set", c.BySet)
+	table("category", "Category", c.ByCategory)
+	table("rarity", "Rarity", c.ByRarity)
+
+	b.WriteString("\n## Unparsed text\n\n")
+	if len(c.Clusters) == 0 {
+		b.WriteString("Every effect text is fully parsed.\n")
+		return b.String()
+	}
+	b.WriteString("Unparsed texts and clauses, grouped by similar wording. Numbers are shown as `#` and energy symbols as `{*}`.\n")
+	for i, cluster := range c.Clusters {
+		fmt.Fprintf(&b, "\n### %d. %s\n\n", i+1, cluster.Shape)
+		fmt.Fprintf(&b, "%d text(s) on %d card(s):\n\n", len(cluster.Texts), cluster.Cards)
+		for _, text := range cluster.Texts {
+			fmt.Fprintf(&b, "- %s (%s)\n", text.Text, strings.Join(text.Cards, ", "))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package effects
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+func TestMeasureCoverage(t *testing.T) {
+	attack := func(effect string) []tcgdex.Attack {
+		return []tcgdex.Attack{{Name: "Attack", Effect: effect}}
+	}
+	a1 := tcgdex.Set{ID: "A1", Name: "Genetic Apex"}
+	a2 := tcgdex.Set{ID: "A2", Name: "Space-Time Smackdown"}
+	cards := []tcgdex.Card{
+		{ID: "A1-001", Set: a1, Rarity: "One Diamond", Attacks: attack("Heal 30 damage from this Pokémon.")},
+		{ID: "A1-002", Set: a1, Rarity: "One Diamond", Attacks: attack("Shuffle 2 {G} Energy into your opponent's deck.")},
+		{ID: "A1-003", Set: a1, Rarity: "Two Diamond", Attacks: attack("Shuffle 3 {R} Energy into your opponent's deck.")},
+		{ID: "A2-001", Set: a2, Attacks: attack("Heal 30 damage from this Pokémon. Your opponent sings a song.")},
+		{ID: "A2-002", Set: a2, Category: "Trainer", TrainerType: "Item", Text: "Heal 20 damage from 1 of your Pokémon."},
+	}
+	coverage := MeasureCoverage(cards)
+
+	want := CoverageStats{Texts: 5, Parsed: 2, Partial: 1, Unknown: 2, Coverage: 40}
+	if coverage.CoverageStats != want {
+		t.Errorf("MeasureCoverage = %+v, want %+v", coverage.CoverageStats, want)
+	}
+
+	keys := func(groups []CoverageGroup) (keys []string) {
+		for _, group := range groups {
+			keys = append(keys, group.Key)
+		}
+		return keys
+	}
+	if got := keys(coverage.BySet); !reflect.DeepEqual(got, []string{"A1", "A2"}) {
+		t.Errorf("BySet keys = %q", got)
+	}
+	if got := keys(coverage.ByCategory); !reflect.DeepEqual(got, []string{"Attack", "Item"}) {
+		t.Errorf("ByCategory keys = %q", got)
+	}
+	if got := keys(coverage.ByRarity); !reflect.DeepEqual(got, []string{"One Diamond", "Two Diamond", "None"}) {
+		t.Errorf("ByRarity keys = %q", got)
+	}
+	if got := coverage.BySet[0]; got.Name != "Genetic Apex" || got.Parsed != 1 || got.Unknown != 2 {
+		t.Errorf("BySet[0] = %+v, want Genetic Apex with 1 parsed and 2 unknown", got)
+	}
+
+	// The two Shuffle texts differ only in their numbers and energy, so they
+	// share a cluster, which leads the one partly parsed clause.
+	if len(coverage.Clusters) != 2 {
+		t.Fatalf("Clusters = %+v, want 2", coverage.Clusters)
+	}
+	shuffle := coverage.Clusters[0]
+	if shuffle.Shape != "Shuffle # {*} Energy into your opponent's deck." || shuffle.Cards != 2 || len(shuffle.Texts) != 2 {
+		t.Errorf("Clusters[0] = %+v, want both Shuffle texts", shuffle)
+	}
+	song := coverage.Clusters[1]
+	if want := []UnknownText{{Text: "Your opponent sings a song.", Cards: []string{"A2-001"}}}; !reflect.DeepEqual(song.Texts, want) {
+		t.Errorf("Clusters[1].Texts = %+v, want %+v", song.Texts, want)
+	}
+
+	markdown := coverage.Markdown()
+	for _, want := range []string{"2 of 5 effect texts (40.0%)", "| Genetic Apex (A1) | 3 | 1 | 0 | 2 | 33.3% |", "- Your opponent sings a song. (A2-001)"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown() is missing %q:\n%s", want, markdown)
+		}
+	}
+}
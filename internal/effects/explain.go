@@ -0,0 +1,88 @@
+package effects
+
+import (
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// Explanation shows how one ability, attack or Trainer card text parses.
+type Explanation struct {
+	Kind string `json:"kind"`           // "ability", "attack" or "trainer"
+	Name string `json:"name,omitempty"` // The ability or attack, or empty for a Trainer card's text
+	Text string `json:"text"`
+	// Effects are the parsed effects, each with its provenance.
+	Effects  []core.Effect `json:"effects"`
+	Unparsed []string      `json:"unparsed,omitempty"`
+	// Clauses lists the rules that match each clause of the text, including
+	// those that lose out to an earlier rule. For a Trainer card, they start
+	// with the trainer rules matching the text, or each of its "Choose 1:"
+	// options, as a whole, which are tried first.
+	Clauses []ClauseRules `json:"clauses"`
+}
+
+// ClauseRules is a clause of an effect text and the rules that match it.
+type ClauseRules struct {
+	Text  string `json:"text"`
+	Scope string `json:"scope,omitempty"` // The scope of the rules, such as ScopeTrainer
+	// Rules lists the matching rules in the order they're tried; the first
+	// is the one the parser uses when the clause is parsed on its own.
+	Rules []string `json:"rules"`
+}
+
+// Explain parses each ability, attack and Trainer card text of card as
+// ParseCard does, recording the provenance of every effect whether or not
+// SetProvenance is on.
+func Explain(card tcgdex.Card) []Explanation {
+	var explanations []Explanation
+	explain := func(kind, name, text string) Explanation {
+		n := Normalize(text)
+		explanation := Explanation{Kind: kind, Name: name, Text: text, Clauses: []ClauseRules{}}
+		if kind == "trainer" {
+			for _, body := range trainerBodies(strings.TrimSpace(n.Text)) {
+				explanation.Clauses = append(explanation.Clauses, ClauseRules{
+					Text:  n.Restore(body),
+					Scope: ScopeTrainer,
+					Rules: ruleNames(rules.matching(ScopeTrainer, body)),
+				})
+			}
+		}
+		for _, clause := range SplitClauses(n.Text) {
+			explanation.Clauses = append(explanation.Clauses, ClauseRules{
+				Text:  n.Restore(clause.Text),
+				Rules: ruleNames(rules.Matching(clause.Text)),
+			})
+		}
+		return explanation
+	}
+
+	withName := func(parsed []core.Effect, name string) []core.Effect {
+		for i := range parsed {
+			parsed[i].Name = name
+		}
+		return parsed
+	}
+
+	for _, ability := range card.Abilities {
+		explanation := explain("ability", ability.Name, ability.Effect)
+		result := parseOriginalText(ability.Effect, true)
+		explanation.Effects, explanation.Unparsed = withName(result.Effects, ability.Name), result.Unparsed
+		explanations = append(explanations, explanation)
+	}
+	for _, attack := range card.Attacks {
+		if attack.Effect == "" {
+			continue
+		}
+		explanation := explain("attack", attack.Name, attack.Effect)
+		result := parseOriginalText(attack.Effect, true)
+		explanation.Effects, explanation.Unparsed = withName(result.Effects, attack.Name), result.Unparsed
+		explanations = append(explanations, explanation)
+	}
+	if card.IsTrainer() && card.Text != "" {
+		explanation := explain("trainer", "", card.Text)
+		explanation.Effects = withName(parseTrainerText(card.Text, true), card.Name)
+		explanations = append(explanations, explanation)
+	}
+	return explanations
+}
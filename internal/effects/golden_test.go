@@ -0,0 +1,189 @@
+package effects
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+	"github.com/cpritch/genomon/pkg/tcgdex/tcgdextest"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden with the current parser output")
+
+// goldenEntry is the parse of one distinct effect text.
+type goldenEntry struct {
+	Text     string        `json:"text"`
+	Effects  []core.Effect `json:"effects"`
+	Unparsed []string      `json:"unparsed,omitempty"`
+}
+
+// TestGoldenEffects checks the parse of every ability and attack text in the
+// card pool against testdata/golden/effects.json. After an intended change
+// to the parser, accept the new output with:
+//
+//	go test ./internal/effects -run Golden -update
+func TestGoldenEffects(t *testing.T) {
+	var texts []string
+	for _, card := range loadCardPool(t) {
+		for _, ability := range card.Abilities {
+			texts = append(texts, ability.Effect)
+		}
+		for _, attack := range card.Attacks {
+			texts = append(texts, attack.Effect)
+		}
+	}
+	checkGolden(t, "effects.json", texts, func(text string) goldenEntry {
+		result := ParseText(text)
+		return goldenEntry{Text: text, Effects: result.Effects, Unparsed: result.Unparsed}
+	})
+}
+
+// TestGoldenTrainers checks the parse of every Trainer card text in the card
+// pool against testdata/golden/trainers.json.
+func TestGoldenTrainers(t *testing.T) {
+	var texts []string
+	for _, card := range loadCardPool(t) {
+		if card.IsTrainer() {
+			texts = append(texts, card.Text)
+		}
+	}
+	checkGolden(t, "trainers.json", texts, func(text string) goldenEntry {
+		return goldenEntry{Text: text, Effects: ParseTrainer(text)}
+	})
+}
+
+func loadCardPool(t *testing.T) []tcgdex.Card {
+	t.Helper()
+	cards, err := tcgdextest.LoadFixtures("../../ptcgp-cards.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cards
+}
+
+// checkGolden parses each distinct text and compares the results with the
+// golden file, or rewrites it when -update is given.
+func checkGolden(t *testing.T, name string, texts []string, parse func(string) goldenEntry) {
+	t.Helper()
+	texts = slices.DeleteFunc(slices.Clone(texts), func(text string) bool { return text == "" })
+	slices.Sort(texts)
+	texts = slices.Compact(texts)
+
+	var got []goldenEntry
+	for _, text := range texts {
+		got = append(got, parse(text))
+	}
+
+	path := filepath.Join("testdata", "golden", name)
+	if *update {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("wrote %d entries to %s", len(got), path)
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run with -update to create it", err)
+	}
+	var want []goldenEntry
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	wanted := make(map[string]goldenEntry, len(want))
+	for _, entry := range want {
+		wanted[entry.Text] = entry
+	}
+
+	failed := false
+	for _, entry := range got {
+		golden, ok := wanted[entry.Text]
+		if !ok {
+			t.Errorf("new effect text %q has no golden entry", entry.Text)
+			failed = true
+			continue
+		}
+		delete(wanted, entry.Text)
+		// Comparing the JSON forms treats numbers decoded from the golden
+		// file the same as the ints the parser produces.
+		if diff := diffLines(marshalEntry(t, golden), marshalEntry(t, entry)); diff != "" {
+			t.Errorf("parse of %q changed (-golden +got):\n%s", entry.Text, diff)
+			failed = true
+		}
+	}
+	for _, entry := range want {
+		if _, ok := wanted[entry.Text]; ok {
+			t.Errorf("golden effect text %q is no longer in the card pool", entry.Text)
+			failed = true
+		}
+	}
+	if failed {
+		t.Logf("if these changes are intended, run: go test ./internal/effects -run %s -update", t.Name())
+	}
+}
+
+func marshalEntry(t *testing.T, entry goldenEntry) string {
+	t.Helper()
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// diffLines returns a line diff of a and b, with removed lines marked "-" and
+// added lines "+", or "" if they are the same.
+func diffLines(a, b string) string {
+	if a == b {
+		return ""
+	}
+	x, y := strings.Split(a, "\n"), strings.Split(b, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:]
+	// and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			out.WriteString("  " + x[i] + "\n")
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			out.WriteString("- " + x[i] + "\n")
+			i++
+		default:
+			out.WriteString("+ " + y[j] + "\n")
+			j++
+		}
+	}
+	return out.String()
+}
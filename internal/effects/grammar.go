@@ -0,0 +1,947 @@
+package effects
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+// The rule table spells out every combination of phrases it understands,
+// which is why it has a rule for each thing "for each" can count, and
+// another for each thing "If ..." can check. Underneath, most attack text
+// follows a small grammar, which ParseGrammar implements directly:
+//
+//	text      = sentence { sentence } .
+//	sentence  = flip "." | [ "If" condition "," | "For each heads" "," ] action "." .
+//	flip      = "Flip" ( "a coin" [ "until you get tails" | "for each" quantity ] | N "coins" ) .
+//	condition = "heads" | "tails" | "both of them are heads" | subject state .
+//	action    = "This attack" [ "also" ] "does" N [ "more" ] "damage" [ "to" target ] [ "for each" quantity ]
+//	          | "This Pokémon also does" N "damage to itself"
+//	          | "Heal" N "damage from" target
+//	          | "Draw" ( "a card" | N "cards" )
+//	          | target ( "is" | "are" ) "now" status { ( "," | "and" ) status }
+//	          | "Discard" ( N | "a" | "all" ) [ "random" ] [ energy { "," energy } [ "," ] [ "and" energy ] ]
+//	            "Energy" ( "from" | "attached to" ) target .
+//	target    = "this Pokémon" | "itself" | "it" | "your opponent's Active Pokémon" | "the Defending Pokémon"
+//	          | "your Active Pokémon" | "both Active Pokémon"
+//	          | ( "1" | "each" | "all" | "any" ) "of" ( "your" | "your opponent's" ) [ "Benched" ] [ energy ] "Pokémon" .
+//
+// where a subject is a target, a Pokémon's name or "you", a state is one of
+// the things a Pokémon can have or be ("has damage on it", "is Poisoned",
+// "is a {D} Pokémon", ...), and a quantity is one of the things "for each"
+// can count ("heads", "{G} Energy attached to this Pokémon", "of your
+// Benched Pokémon", ...). The parser builds an AST from the text, which
+// AST.Effects lowers to the same effects the rule table produces, so any
+// combination of known phrases parses whether or not a rule spells it out.
+//
+// The grammar runs alongside the rule table while rules are migrated to it;
+// CompareGrammar reports where the two disagree.
+
+// AST is the parse of an effect text by ParseGrammar.
+type AST struct {
+	Sentences []Sentence `json:"sentences"`
+}
+
+// Sentence is one sentence of an effect text: a coin flip, or an action
+// with the condition it depends on, if any.
+type Sentence struct {
+	Text      string    `json:"text"`
+	Flip      *Flip     `json:"flip,omitempty"`
+	Condition Condition `json:"condition,omitempty"`
+	Action    Action    `json:"action,omitempty"`
+}
+
+// Flip is a sentence that flips coins for the sentences after it.
+type Flip struct {
+	Coins      int       `json:"coins,omitempty"` // The number of coins, if fixed
+	UntilTails bool      `json:"untilTails,omitempty"`
+	Per        *Quantity `json:"per,omitempty"` // What one coin is flipped for each of
+}
+
+// Condition is what must hold for an action to happen: a CoinCondition or a
+// StateCondition.
+type Condition interface {
+	isCondition()
+}
+
+// CoinCondition is the coin flip result an action needs, from the flip
+// before it.
+type CoinCondition struct {
+	Result core.CoinResult `json:"result,omitempty"`
+	// ForEachHeads is true if the action happens once for each heads, as in
+	// "For each heads, ...".
+	ForEachHeads bool `json:"forEachHeads,omitempty"`
+}
+
+// StateCondition is a condition on the game state, such as "this Pokémon
+// has damage on it".
+type StateCondition struct {
+	Subject Target `json:"subject"`
+	State   State  `json:"state"`
+}
+
+func (*CoinCondition) isCondition()  {}
+func (*StateCondition) isCondition() {}
+
+// Action is what a sentence does: a DamageAction, HealAction, DrawAction,
+// StatusAction or DiscardEnergyAction.
+type Action interface {
+	isAction()
+}
+
+// DamageAction is damage done by the attack, or by the attacking Pokémon to
+// itself.
+type DamageAction struct {
+	Amount int       `json:"amount"`
+	More   bool      `json:"more,omitempty"` // On top of the attack's printed damage
+	Also   bool      `json:"also,omitempty"` // To a Pokémon besides the usual one
+	ToSelf bool      `json:"toSelf,omitempty"`
+	To     *Target   `json:"to,omitempty"`  // Who is damaged, if not the opponent's Active Pokémon
+	Per    *Quantity `json:"per,omitempty"` // What Amount is done for each of
+}
+
+// HealAction heals damage from a Pokémon.
+type HealAction struct {
+	Amount int    `json:"amount"`
+	From   Target `json:"from"`
+}
+
+// DrawAction draws cards.
+type DrawAction struct {
+	Count int `json:"count"`
+}
+
+// StatusAction gives Pokémon Special Conditions.
+type StatusAction struct {
+	Target   Target                 `json:"target"`
+	Statuses []core.StatusCondition `json:"statuses"`
+}
+
+// DiscardEnergyAction discards Energy attached to a Pokémon.
+type DiscardEnergyAction struct {
+	Count  int               `json:"count,omitempty"`
+	All    bool              `json:"all,omitempty"`
+	Random bool              `json:"random,omitempty"`
+	Types  []core.EnergyType `json:"types,omitempty"`
+	From   Target            `json:"from"`
+}
+
+func (*DamageAction) isAction()        {}
+func (*HealAction) isAction()          {}
+func (*DrawAction) isAction()          {}
+func (*StatusAction) isAction()        {}
+func (*DiscardEnergyAction) isAction() {}
+
+// Who is the player or Pokémon a target picks out.
+type Who string
+
+const (
+	WhoThisPokemon          Who = "THIS_POKEMON"
+	WhoOpponentActive       Who = "OPPONENT_ACTIVE"
+	WhoYourActive           Who = "YOUR_ACTIVE"
+	WhoBothActive           Who = "BOTH_ACTIVE"
+	WhoOneOpponent          Who = "ONE_OPPONENT"           // 1 of your opponent's Pokémon
+	WhoOneOpponentBenched   Who = "ONE_OPPONENT_BENCHED"   // 1 of your opponent's Benched Pokémon
+	WhoEachOpponent         Who = "EACH_OPPONENT"          // Each of your opponent's Pokémon
+	WhoEachOpponentBenched  Who = "EACH_OPPONENT_BENCHED"  // Each of your opponent's Benched Pokémon
+	WhoOneYours             Who = "ONE_YOURS"              // 1 of your Pokémon
+	WhoOneYourBenched       Who = "ONE_YOUR_BENCHED"       // 1 of your Benched Pokémon
+	WhoEachYours            Who = "EACH_YOURS"             // Each of your Pokémon
+	WhoEachYourBenched      Who = "EACH_YOUR_BENCHED"      // Each of your Benched Pokémon
+	WhoAnyYourBenched       Who = "ANY_YOUR_BENCHED"       // Any of your Benched Pokémon
+	WhoEachBenched          Who = "EACH_BENCHED"           // Benched Pokémon, both yours and your opponent's
+	WhoEvolutionYourBenched Who = "EVOLUTION_YOUR_BENCHED" // Evolution Pokémon on your Bench
+	WhoYours                Who = "YOURS"                  // Pokémon you have in play
+	WhoYou                  Who = "YOU"                    // The player
+	WhoNamed                Who = "NAMED"                  // A Pokémon called by name
+)
+
+// Target is the Pokémon or player an action or condition is about.
+type Target struct {
+	Who   Who             `json:"who"`
+	Type  core.EnergyType `json:"type,omitempty"`  // Only Pokémon of this type
+	Names []string        `json:"names,omitempty"` // Only Pokémon with these names
+}
+
+// Counted is what a quantity counts.
+type Counted string
+
+const (
+	CountHeads       Counted = "HEADS"
+	CountEnergy      Counted = "ENERGY"       // Energy attached to Of
+	CountRetreatCost Counted = "RETREAT_COST" // Energy in the Retreat Cost of Of
+	CountPokemon     Counted = "POKEMON"      // The Pokémon Of picks out
+)
+
+// Quantity is what "for each" counts.
+type Quantity struct {
+	Count Counted         `json:"count"`
+	Of    *Target         `json:"of,omitempty"`
+	Type  core.EnergyType `json:"type,omitempty"` // Only Energy of this type
+}
+
+// StateKind is a kind of state a condition checks for.
+type StateKind string
+
+const (
+	StateHasDamage            StateKind = "HAS_DAMAGE"
+	StateHasNoDamage          StateKind = "HAS_NO_DAMAGE"
+	StateHasTool              StateKind = "HAS_TOOL"
+	StateHasAbility           StateKind = "HAS_ABILITY"
+	StateHasEnergy            StateKind = "HAS_ENERGY"       // Any Energy of Type attached
+	StateHasExtraEnergy       StateKind = "HAS_EXTRA_ENERGY" // At least Count extra Energy of Type attached
+	StateDifferentEnergyTypes StateKind = "DIFFERENT_ENERGY_TYPES"
+	StateMoreRemainingHP      StateKind = "MORE_REMAINING_HP" // More remaining HP than this Pokémon
+	StateStatus               StateKind = "STATUS"
+	StateSpecialCondition     StateKind = "SPECIAL_CONDITION"
+	StateStage                StateKind = "STAGE"
+	StateProperty             StateKind = "PROPERTY" // A type, "a Pokémon ex" or "an Evolution Pokémon", as written in Text
+	StateNamed                StateKind = "NAMED"
+	StateOnBench              StateKind = "ON_BENCH"
+	StateEvolvedThisTurn      StateKind = "EVOLVED_THIS_TURN"
+	StateMovedToActive        StateKind = "MOVED_TO_ACTIVE"
+	StatePlayedSupporter      StateKind = "PLAYED_SUPPORTER"
+)
+
+// State is what a StateCondition checks its subject for.
+type State struct {
+	Kind   StateKind            `json:"kind"`
+	Status core.StatusCondition `json:"status,omitempty"`
+	Type   core.EnergyType      `json:"type,omitempty"`
+	Stage  string               `json:"stage,omitempty"`
+	Name   string               `json:"name,omitempty"`
+	Count  int                  `json:"count,omitempty"`
+	Text   string               `json:"text,omitempty"`
+}
+
+// GrammarError reports where effect text stops following the grammar.
+type GrammarError struct {
+	Offset   int      // The byte offset in the normalised text
+	Found    string   // The text found there
+	Expected []string // What the grammar could have continued with
+}
+
+func (e *GrammarError) Error() string {
+	found := strconv.Quote(e.Found)
+	if e.Found == "" {
+		found = "the end of the text"
+	}
+	return fmt.Sprintf("at byte %d: expected %s, found %s", e.Offset, strings.Join(e.Expected, " or "), found)
+}
+
+// ParseGrammar parses effect text, once normalised with Normalize, with the
+// effect grammar. The error is a *GrammarError if the text doesn't follow
+// it.
+func ParseGrammar(text string) (*AST, error) {
+	normalized := Normalize(text).Text
+	p := &grammarParser{text: normalized, tokens: tokenize(normalized)}
+	ast := &AST{}
+	for !p.done() {
+		sentence, ok := p.sentence()
+		if !ok {
+			return nil, p.err()
+		}
+		ast.Sentences = append(ast.Sentences, sentence)
+	}
+	if len(ast.Sentences) == 0 {
+		return nil, p.err()
+	}
+	return ast, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota
+	tokenNumber           // Digits, which may have a sign
+	tokenSymbol           // Text in braces, such as "{R}"
+	tokenPunct
+)
+
+// token is a word, number, symbol or punctuation mark of effect text.
+type token struct {
+	kind       tokenKind
+	text       string
+	start, end int // Byte offsets in the text
+}
+
+// tokenize splits normalised effect text into tokens. Words keep their
+// apostrophes, so "opponent's" is one token.
+func tokenize(text string) []token {
+	var tokens []token
+	runes := []rune(text)
+	offset := func(i int) int { return len(string(runes[:i])) }
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '{':
+			for i < len(runes) && runes[i] != '}' {
+				i++
+			}
+			i = min(i+1, len(runes))
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(runes[start:i])})
+		case unicode.IsDigit(r) || (r == '−' || r == '+') && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			i++
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i])})
+		case unicode.IsLetter(r):
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) ||
+				runes[i] == '\'' && i+1 < len(runes) && unicode.IsLetter(runes[i+1])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i])})
+		default:
+			i++
+			tokens = append(tokens, token{kind: tokenPunct, text: string(r)})
+		}
+		tokens[len(tokens)-1].start, tokens[len(tokens)-1].end = offset(start), offset(i)
+	}
+	return tokens
+}
+
+// grammarParser is a recursive descent parser over the tokens of one text.
+// Each production either consumes what it matched and returns true, or
+// leaves the position where it was and returns false.
+type grammarParser struct {
+	text   string
+	tokens []token
+	pos    int
+
+	// The furthest position any production failed at, and what it
+	// expected there, for the error.
+	furthest int
+	expected []string
+}
+
+func (p *grammarParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *grammarParser) peek() (token, bool) {
+	if p.done() {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// fail records that the grammar expected what at the current position.
+func (p *grammarParser) fail(what string) {
+	p.failAt(p.pos, what)
+}
+
+// failAt records that the grammar expected what at the token at pos.
+func (p *grammarParser) failAt(pos int, what string) {
+	switch {
+	case pos > p.furthest:
+		p.furthest, p.expected = pos, []string{what}
+	case pos == p.furthest:
+		for _, e := range p.expected {
+			if e == what {
+				return
+			}
+		}
+		p.expected = append(p.expected, what)
+	}
+}
+
+// mark is where a production started, and how far parsing had got then.
+type mark struct {
+	pos, furthest, expected int
+}
+
+func (p *grammarParser) mark() mark {
+	return mark{pos: p.pos, furthest: p.furthest, expected: len(p.expected)}
+}
+
+// describe sums up what the grammar expected as what, if the production
+// that started at m failed without getting past its first token. Deferred
+// with a pointer to the production's result, it keeps errors to one
+// expectation per production rather than every phrase it tried.
+func (p *grammarParser) describe(m mark, what string, ok *bool) {
+	if *ok || p.furthest != m.pos {
+		return
+	}
+	if m.furthest == m.pos {
+		p.expected = p.expected[:m.expected]
+	} else {
+		p.expected = nil
+	}
+	p.failAt(m.pos, what)
+}
+
+func (p *grammarParser) err() *GrammarError {
+	e := &GrammarError{Offset: len(p.text), Expected: p.expected}
+	if p.furthest < len(p.tokens) {
+		e.Offset = p.tokens[p.furthest].start
+		e.Found = p.tokens[p.furthest].text
+	}
+	if len(e.Expected) == 0 {
+		e.Expected = []string{"a sentence"}
+	}
+	return e
+}
+
+// phrase consumes the words and punctuation of phrase, separated by spaces
+// and matched regardless of case.
+func (p *grammarParser) phrase(phrase string) bool {
+	start := p.pos
+	for _, part := range strings.Fields(phrase) {
+		tok, ok := p.peek()
+		if !ok || !strings.EqualFold(tok.text, part) {
+			// Report the failure at the word that doesn't match, so that
+			// the error points past the part of the phrase that does.
+			p.failAt(p.pos, strconv.Quote(phrase))
+			p.pos = start
+			return false
+		}
+		p.pos++
+	}
+	return true
+}
+
+// oneOf consumes the first of phrases that matches, returning its index, or
+// -1 if none does.
+func (p *grammarParser) oneOf(phrases ...string) int {
+	for i, phrase := range phrases {
+		if p.phrase(phrase) {
+			return i
+		}
+	}
+	return -1
+}
+
+// number consumes a number, or "a" or "an" for 1 if article is set.
+func (p *grammarParser) number(article bool) (int, bool) {
+	tok, ok := p.peek()
+	if ok && tok.kind == tokenNumber {
+		if n, err := strconv.Atoi(strings.TrimPrefix(tok.text, "+")); err == nil {
+			p.pos++
+			return n, true
+		}
+	}
+	if article && p.oneOf("a", "an") >= 0 {
+		return 1, true
+	}
+	p.fail("a number")
+	return 0, false
+}
+
+// energySymbol consumes an energy type written as a symbol, such as "{R}".
+func (p *grammarParser) energySymbol() (core.EnergyType, bool) {
+	tok, ok := p.peek()
+	if ok && tok.kind == tokenSymbol {
+		if t, err := core.ParseEnergyType(tok.text); err == nil {
+			p.pos++
+			return t, true
+		}
+	}
+	p.fail("an energy symbol")
+	return "", false
+}
+
+// status consumes the name of a Special Condition.
+func (p *grammarParser) status() (_ core.StatusCondition, ok bool) {
+	defer p.describe(p.mark(), "a Special Condition", &ok)
+	for _, status := range []core.StatusCondition{core.StatusAsleep, core.StatusBurned, core.StatusConfused, core.StatusParalyzed, core.StatusPoisoned} {
+		if p.phrase(string(status)) {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+// name consumes a Pokémon's name: capitalised words that aren't part of the
+// grammar, optionally followed by "ex".
+func (p *grammarParser) name() (string, bool) {
+	start := p.pos
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind != tokenWord || !unicode.IsUpper([]rune(tok.text)[0]) || grammarKeywords[strings.ToLower(tok.text)] {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		p.fail("a Pokémon's name")
+		return "", false
+	}
+	if tok, ok := p.peek(); ok && tok.text == "ex" {
+		p.pos++
+	}
+	return p.text[p.tokens[start].start:p.tokens[p.pos-1].end], true
+}
+
+// grammarKeywords are the capitalised words of the grammar, which can't be
+// part of a name.
+var grammarKeywords = map[string]bool{
+	"pokémon": true, "active": true, "benched": true, "bench": true, "basic": true, "stage": true,
+	"evolution": true, "energy": true, "if": true, "this": true, "your": true, "the": true,
+	"asleep": true, "burned": true, "confused": true, "paralyzed": true, "poisoned": true,
+}
+
+// names consumes a list of names, such as "Wishiwashi and Wishiwashi ex".
+func (p *grammarParser) names() ([]string, bool) {
+	var names []string
+	for {
+		name, ok := p.name()
+		if !ok {
+			return nil, false
+		}
+		names = append(names, name)
+		if p.oneOf(", and", ",", "and", "or") < 0 {
+			return names, true
+		}
+	}
+}
+
+// sentence parses one sentence, up to and including its full stop.
+func (p *grammarParser) sentence() (_ Sentence, ok bool) {
+	defer p.describe(p.mark(), "a coin flip, a condition or an action", &ok)
+	start := p.pos
+	var sentence Sentence
+	if flip, ok := p.flip(); ok {
+		sentence.Flip = flip
+	} else {
+		if p.phrase("if") {
+			condition, ok := p.condition()
+			if !ok || !p.phrase(",") {
+				p.pos = start
+				return sentence, false
+			}
+			sentence.Condition = condition
+		} else if p.phrase("for each heads ,") {
+			sentence.Condition = &CoinCondition{ForEachHeads: true}
+		}
+		action, ok := p.action()
+		if !ok {
+			p.pos = start
+			return sentence, false
+		}
+		sentence.Action = action
+	}
+	if !p.phrase(".") {
+		p.pos = start
+		return sentence, false
+	}
+	sentence.Text = p.text[p.tokens[start].start:p.tokens[p.pos-1].end]
+	return sentence, true
+}
+
+// flip parses a coin flip.
+func (p *grammarParser) flip() (*Flip, bool) {
+	start := p.pos
+	if !p.phrase("flip") {
+		return nil, false
+	}
+	if p.phrase("a coin") {
+		if p.phrase("until you get tails") {
+			return &Flip{UntilTails: true}, true
+		}
+		if p.phrase("for each") {
+			if per, ok := p.quantity(); ok {
+				return &Flip{Per: per}, true
+			}
+			p.pos = start
+			return nil, false
+		}
+		return &Flip{Coins: 1}, true
+	}
+	if coins, ok := p.number(false); ok && p.phrase("coins") {
+		return &Flip{Coins: coins}, true
+	}
+	p.pos = start
+	return nil, false
+}
+
+// condition parses what follows "If".
+func (p *grammarParser) condition() (_ Condition, ok bool) {
+	defer p.describe(p.mark(), "a condition, such as \"heads\"", &ok)
+	switch p.oneOf("heads", "tails", "both of them are heads") {
+	case 0:
+		return &CoinCondition{Result: core.CoinHeads}, true
+	case 1:
+		return &CoinCondition{Result: core.CoinTails}, true
+	case 2:
+		return &CoinCondition{Result: core.CoinDoubleHeads}, true
+	}
+
+	start := p.pos
+	subject, found := p.subject()
+	if !found {
+		return nil, false
+	}
+	state, found := p.state(subject)
+	if !found {
+		p.pos = start
+		return nil, false
+	}
+	return &StateCondition{Subject: subject, State: state}, true
+}
+
+// subject parses what a condition is about.
+func (p *grammarParser) subject() (Target, bool) {
+	if p.phrase("you") {
+		return Target{Who: WhoYou}, true
+	}
+	if p.phrase("any of your benched pokémon") {
+		return Target{Who: WhoAnyYourBenched}, true
+	}
+	if target, ok := p.target(); ok {
+		return target, true
+	}
+	if name, ok := p.name(); ok {
+		return Target{Who: WhoNamed, Names: []string{name}}, true
+	}
+	return Target{}, false
+}
+
+// state parses what a condition checks its subject for.
+func (p *grammarParser) state(subject Target) (_ State, ok bool) {
+	defer p.describe(p.mark(), "a state, such as \"has damage on it\"", &ok)
+	start := p.pos
+	switch subject.Who {
+	case WhoYou:
+		if p.phrase("played a supporter card from your hand during this turn") {
+			return State{Kind: StatePlayedSupporter}, true
+		}
+		return State{}, false
+	case WhoAnyYourBenched:
+		if p.phrase("have damage on them") {
+			return State{Kind: StateHasDamage}, true
+		}
+		return State{}, false
+	case WhoNamed:
+		if p.phrase("is on your bench") {
+			return State{Kind: StateOnBench}, true
+		}
+		return State{}, false
+	}
+
+	switch p.oneOf(
+		"has damage on it", "has no damage on it", "has a pokémon tool attached", "has an ability",
+		"is affected by a special condition", "evolved during this turn",
+		"moved from your bench to the active spot this turn", "has more remaining hp than this pokémon",
+	) {
+	case 0:
+		return State{Kind: StateHasDamage}, true
+	case 1:
+		return State{Kind: StateHasNoDamage}, true
+	case 2:
+		return State{Kind: StateHasTool}, true
+	case 3:
+		return State{Kind: StateHasAbility}, true
+	case 4:
+		return State{Kind: StateSpecialCondition}, true
+	case 5:
+		return State{Kind: StateEvolvedThisTurn}, true
+	case 6:
+		return State{Kind: StateMovedToActive}, true
+	case 7:
+		return State{Kind: StateMoreRemainingHP}, true
+	}
+
+	if p.phrase("has any") {
+		if t, ok := p.energySymbol(); ok && p.phrase("energy attached") {
+			return State{Kind: StateHasEnergy, Type: t}, true
+		}
+	} else if p.phrase("has at least") {
+		if n, ok := p.number(false); ok && p.phrase("extra") {
+			if t, ok := p.energySymbol(); ok && p.phrase("energy attached") {
+				return State{Kind: StateHasExtraEnergy, Type: t, Count: n}, true
+			}
+		}
+	} else if p.phrase("has") {
+		if n, ok := p.number(false); ok && p.phrase("or more different types of energy attached") {
+			return State{Kind: StateDifferentEnergyTypes, Count: n}, true
+		}
+	} else if p.phrase("is") {
+		if status, ok := p.status(); ok {
+			return State{Kind: StateStatus, Status: status}, true
+		}
+		kindStart := p.pos
+		if p.phrase("a") {
+			if stage := p.oneOf("basic pokémon", "stage 1 pokémon", "stage 2 pokémon"); stage >= 0 {
+				return State{Kind: StateStage, Stage: []string{"Basic", "Stage1", "Stage2"}[stage]}, true
+			}
+			if _, ok := p.energySymbol(); ok && p.phrase("pokémon") {
+				return State{Kind: StateProperty, Text: p.span(kindStart)}, true
+			}
+			if p.phrase("pokémon") && p.oneOf("ex", "{ex}") >= 0 {
+				return State{Kind: StateProperty, Text: p.span(kindStart)}, true
+			}
+		} else if p.phrase("an evolution pokémon") {
+			return State{Kind: StateProperty, Text: p.span(kindStart)}, true
+		} else if name, ok := p.name(); ok {
+			return State{Kind: StateNamed, Name: name}, true
+		}
+	}
+	p.pos = start
+	return State{}, false
+}
+
+// span returns the text from the token at start to the current position.
+func (p *grammarParser) span(start int) string {
+	return p.text[p.tokens[start].start:p.tokens[p.pos-1].end]
+}
+
+// action parses what a sentence does.
+func (p *grammarParser) action() (_ Action, ok bool) {
+	defer p.describe(p.mark(), "an action, such as \"this attack does\"", &ok)
+	for _, action := range []func() (Action, bool){p.damage, p.heal, p.draw, p.discardEnergy, p.applyStatus} {
+		if parsed, ok := action(); ok {
+			return parsed, true
+		}
+	}
+	return nil, false
+}
+
+func (p *grammarParser) damage() (Action, bool) {
+	start := p.pos
+	if p.phrase("this pokémon also does") {
+		if n, ok := p.number(false); ok && p.phrase("damage to itself") {
+			return &DamageAction{Amount: n, Also: true, ToSelf: true}, true
+		}
+		p.pos = start
+		return nil, false
+	}
+	if !p.phrase("this attack") {
+		return nil, false
+	}
+	damage := &DamageAction{Also: p.phrase("also")}
+	var ok bool
+	if !p.phrase("does") {
+		p.pos = start
+		return nil, false
+	}
+	if damage.Amount, ok = p.number(false); !ok {
+		p.pos = start
+		return nil, false
+	}
+	damage.More = p.phrase("more")
+	if !p.phrase("damage") {
+		p.pos = start
+		return nil, false
+	}
+	if p.phrase("to") {
+		to, ok := p.target()
+		if !ok {
+			p.pos = start
+			return nil, false
+		}
+		damage.To = &to
+	}
+	if p.phrase("for each") {
+		per, ok := p.quantity()
+		if !ok {
+			p.pos = start
+			return nil, false
+		}
+		damage.Per = per
+	}
+	return damage, true
+}
+
+func (p *grammarParser) heal() (Action, bool) {
+	start := p.pos
+	if !p.phrase("heal") {
+		return nil, false
+	}
+	if n, ok := p.number(false); ok && p.phrase("damage from") {
+		if from, ok := p.target(); ok {
+			return &HealAction{Amount: n, From: from}, true
+		}
+	}
+	p.pos = start
+	return nil, false
+}
+
+func (p *grammarParser) draw() (Action, bool) {
+	start := p.pos
+	if !p.phrase("draw") {
+		return nil, false
+	}
+	if p.phrase("a card") {
+		return &DrawAction{Count: 1}, true
+	}
+	if n, ok := p.number(false); ok && p.phrase("cards") {
+		return &DrawAction{Count: n}, true
+	}
+	p.pos = start
+	return nil, false
+}
+
+func (p *grammarParser) discardEnergy() (Action, bool) {
+	start := p.pos
+	if !p.phrase("discard") {
+		return nil, false
+	}
+	discard := &DiscardEnergyAction{}
+	if p.phrase("all") {
+		discard.All = true
+	} else if n, ok := p.number(true); ok {
+		discard.Count = n
+	} else {
+		p.pos = start
+		return nil, false
+	}
+	discard.Random = p.phrase("random")
+	for {
+		t, ok := p.energySymbol()
+		if !ok {
+			break
+		}
+		discard.Types = append(discard.Types, t)
+		if p.oneOf(", and", ",", "and") < 0 {
+			break
+		}
+	}
+	if len(discard.Types) > 1 {
+		discard.Count = len(discard.Types)
+	}
+	if !p.phrase("energy") || p.oneOf("from", "attached to") < 0 {
+		p.pos = start
+		return nil, false
+	}
+	from, ok := p.target()
+	if !ok {
+		p.pos = start
+		return nil, false
+	}
+	discard.From = from
+	return discard, true
+}
+
+func (p *grammarParser) applyStatus() (Action, bool) {
+	start := p.pos
+	target, ok := p.target()
+	if !ok || p.oneOf("is now", "are now") < 0 {
+		p.pos = start
+		return nil, false
+	}
+	action := &StatusAction{Target: target}
+	for {
+		status, ok := p.status()
+		if !ok {
+			p.pos = start
+			return nil, false
+		}
+		action.Statuses = append(action.Statuses, status)
+		if p.oneOf(", and", ",", "and") < 0 {
+			return action, true
+		}
+	}
+}
+
+// target parses the Pokémon an action or condition is about.
+func (p *grammarParser) target() (_ Target, ok bool) {
+	defer p.describe(p.mark(), "a Pokémon, such as \"this Pokémon\"", &ok)
+	switch p.oneOf("this pokémon", "itself", "it", "your opponent's active pokémon", "the defending pokémon",
+		"your active pokémon", "both active pokémon") {
+	case 0, 1, 2:
+		return Target{Who: WhoThisPokemon}, true
+	case 3, 4:
+		return Target{Who: WhoOpponentActive}, true
+	case 5:
+		return Target{Who: WhoYourActive}, true
+	case 6:
+		return Target{Who: WhoBothActive}, true
+	}
+
+	start := p.pos
+	which := p.oneOf("1 of", "each of", "all of", "any of")
+	if which < 0 {
+		return Target{}, false
+	}
+	opponent := p.phrase("your opponent's")
+	if !opponent && !p.phrase("your") {
+		p.pos = start
+		return Target{}, false
+	}
+	benched := p.phrase("benched")
+	t, _ := p.energySymbol()
+	if !p.phrase("pokémon") {
+		p.pos = start
+		return Target{}, false
+	}
+	one := which == 0
+	var who Who
+	switch {
+	case opponent && benched && one:
+		who = WhoOneOpponentBenched
+	case opponent && benched:
+		who = WhoEachOpponentBenched
+	case opponent && one:
+		who = WhoOneOpponent
+	case opponent:
+		who = WhoEachOpponent
+	case benched && one:
+		who = WhoOneYourBenched
+	case benched:
+		who = WhoEachYourBenched
+	case one:
+		who = WhoOneYours
+	default:
+		who = WhoEachYours
+	}
+	return Target{Who: who, Type: t}, true
+}
+
+// quantity parses what follows "for each".
+func (p *grammarParser) quantity() (_ *Quantity, ok bool) {
+	defer p.describe(p.mark(), "something to count, such as \"heads\"", &ok)
+	start := p.pos
+	if p.phrase("heads") {
+		return &Quantity{Count: CountHeads}, true
+	}
+	if p.phrase("pokémon you have in play") {
+		return &Quantity{Count: CountPokemon, Of: &Target{Who: WhoYours}}, true
+	}
+	if p.phrase("benched pokémon ( both yours and your opponent's )") {
+		return &Quantity{Count: CountPokemon, Of: &Target{Who: WhoEachBenched}}, true
+	}
+	if p.phrase("evolution pokémon on your bench") {
+		return &Quantity{Count: CountPokemon, Of: &Target{Who: WhoEvolutionYourBenched}}, true
+	}
+	if p.phrase("of") {
+		opponent := p.phrase("your opponent's")
+		if (opponent || p.phrase("your")) && p.phrase("benched") {
+			who := WhoEachYourBenched
+			if opponent {
+				who = WhoEachOpponentBenched
+			}
+			t, typed := p.energySymbol()
+			if p.phrase("pokémon") {
+				return &Quantity{Count: CountPokemon, Of: &Target{Who: who, Type: t}}, true
+			}
+			if names, ok := p.names(); ok && !typed {
+				return &Quantity{Count: CountPokemon, Of: &Target{Who: who, Names: names}}, true
+			}
+		}
+		p.pos = start
+		return nil, false
+	}
+
+	t, _ := p.energySymbol()
+	if !p.phrase("energy") {
+		p.pos = start
+		return nil, false
+	}
+	if p.phrase("attached to") {
+		if of, ok := p.target(); ok {
+			return &Quantity{Count: CountEnergy, Of: &of, Type: t}, true
+		}
+	} else if p.phrase("in your opponent's active pokémon's retreat cost") {
+		return &Quantity{Count: CountRetreatCost, Of: &Target{Who: WhoOpponentActive}}, true
+	}
+	p.pos = start
+	return nil, false
+}
@@ -0,0 +1,206 @@
+package effects
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// GrammarOutcome is how the grammar's parse of a text compares with the rule
+// table's.
+type GrammarOutcome string
+
+const (
+	GrammarSame      GrammarOutcome = "same"         // Both parse the text to the same effects
+	GrammarDifferent GrammarOutcome = "different"    // Both parse the text, to different effects
+	GrammarOnly      GrammarOutcome = "grammar-only" // Only the grammar parses the text
+	GrammarRulesOnly GrammarOutcome = "rules-only"   // Only the rule table parses the text
+	GrammarNeither   GrammarOutcome = "neither"      // Neither parses the text
+)
+
+// GrammarComparison reports how the grammar parser compares with the rule
+// table on a body of effect texts.
+type GrammarComparison struct {
+	Texts  int                    `json:"texts"` // The number of distinct texts compared
+	Counts map[GrammarOutcome]int `json:"counts"`
+	// Results lists every text whose parses aren't the same, sorted by
+	// outcome and then text.
+	Results []GrammarResult `json:"results"`
+}
+
+// GrammarResult compares the grammar's and the rule table's parse of a text.
+type GrammarResult struct {
+	Text    string         `json:"text"`
+	Outcome GrammarOutcome `json:"outcome"`
+	// Differences lists the fields, as paths into the effects, in which the
+	// parses differ.
+	Differences []string      `json:"differences,omitempty"`
+	Grammar     []core.Effect `json:"grammar,omitempty"`
+	Rules       []core.Effect `json:"rules,omitempty"`
+	Error       string        `json:"error,omitempty"` // Why the grammar couldn't parse the text
+}
+
+// CompareGrammar parses every distinct ability, attack and Trainer card text
+// in cards with both the grammar and the rule table, and reports where they
+// differ. Trainer card text is parsed by the rules as ParseTrainer does.
+// Effects are compared without their names, descriptions, provenance and
+// dependencies, which the grammar doesn't fill in the same way.
+func CompareGrammar(cards []tcgdex.Card) *GrammarComparison {
+	comparison := &GrammarComparison{Counts: make(map[GrammarOutcome]int)}
+	seen := make(map[string]bool)
+	compare := func(text string, trainer bool) {
+		if text == "" || seen[text] {
+			return
+		}
+		seen[text] = true
+		comparison.Texts++
+		result := compareGrammar(text, trainer)
+		comparison.Counts[result.Outcome]++
+		if result.Outcome != GrammarSame {
+			comparison.Results = append(comparison.Results, result)
+		}
+	}
+	for _, card := range cards {
+		for _, ability := range card.Abilities {
+			compare(ability.Effect, false)
+		}
+		for _, attack := range card.Attacks {
+			compare(attack.Effect, false)
+		}
+		if card.IsTrainer() {
+			compare(card.Text, true)
+		}
+	}
+
+	sort.Slice(comparison.Results, func(i, j int) bool {
+		a, b := comparison.Results[i], comparison.Results[j]
+		if a.Outcome != b.Outcome {
+			return a.Outcome < b.Outcome
+		}
+		return a.Text < b.Text
+	})
+	return comparison
+}
+
+func compareGrammar(text string, trainer bool) GrammarResult {
+	result := GrammarResult{Text: text}
+
+	var parsed Parsed
+	if trainer {
+		parsed.Effects = ParseTrainer(text)
+	} else {
+		parsed = ParseText(text)
+	}
+	rulesParsed := len(parsed.Unparsed) == 0
+	for _, effect := range parsed.Effects {
+		if effect.Type == core.EffectUnknown {
+			rulesParsed = false
+		}
+	}
+	if rulesParsed {
+		result.Rules = comparableEffects(parsed.Effects)
+	}
+
+	ast, err := ParseGrammar(text)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Grammar = comparableEffects(ast.Effects())
+	}
+
+	switch {
+	case err != nil && !rulesParsed:
+		result.Outcome = GrammarNeither
+	case err != nil:
+		result.Outcome = GrammarRulesOnly
+	case !rulesParsed:
+		result.Outcome = GrammarOnly
+	default:
+		result.Differences = effectDifferences(result.Grammar, result.Rules)
+		result.Outcome = GrammarSame
+		if len(result.Differences) > 0 {
+			result.Outcome = GrammarDifferent
+		}
+	}
+	return result
+}
+
+// comparableEffects copies parsed without the fields CompareGrammar ignores.
+func comparableEffects(parsed []core.Effect) []core.Effect {
+	stripped := make([]core.Effect, len(parsed))
+	for i, effect := range parsed {
+		effect.Name, effect.Description, effect.Provenance = "", "", nil
+		if effect.Conditions != nil && effect.Conditions.DependsOn != nil {
+			conditions := *effect.Conditions
+			conditions.DependsOn = nil
+			effect.Conditions = &conditions
+			if conditions == (core.Conditions{}) {
+				effect.Conditions = nil
+			}
+		}
+		stripped[i] = effect
+	}
+	return stripped
+}
+
+// effectDifferences lists the paths, such as
+// "effects[0].conditions.scaling.base", at which the JSON of a and b
+// differs. A list of a different length differs as a whole.
+func effectDifferences(a, b []core.Effect) []string {
+	var differences []string
+	var walk func(path string, x, y any)
+	walk = func(path string, x, y any) {
+		switch x := x.(type) {
+		case map[string]any:
+			y, ok := y.(map[string]any)
+			if !ok {
+				break
+			}
+			keys := make([]string, 0, len(x)+len(y))
+			for key := range x {
+				keys = append(keys, key)
+			}
+			for key := range y {
+				if _, ok := x[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(path+"."+key, x[key], y[key])
+			}
+			return
+		case []any:
+			y, ok := y.([]any)
+			if !ok || len(x) != len(y) {
+				break
+			}
+			for i := range x {
+				walk(fmt.Sprintf("%s[%d]", path, i), x[i], y[i])
+			}
+			return
+		}
+		if !reflect.DeepEqual(x, y) {
+			differences = append(differences, path)
+		}
+	}
+	walk("effects", asJSONValue(a), asJSONValue(b))
+	return differences
+}
+
+// asJSONValue converts v to the maps, slices and values its JSON decodes to.
+func asJSONValue(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	return value
+}
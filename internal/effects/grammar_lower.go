@@ -0,0 +1,349 @@
+package effects
+
+import (
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+// Effects lowers the AST to effects, following the same conventions as the
+// rule table so that the two can be compared effect for effect.
+func (a *AST) Effects() []core.Effect {
+	var parsed []core.Effect
+	var flip *Flip
+	for _, sentence := range a.Sentences {
+		if sentence.Flip != nil {
+			flip = sentence.Flip
+			continue
+		}
+		parsed = append(parsed, lowerSentence(sentence, flip)...)
+	}
+	return parsed
+}
+
+// lowerSentence lowers one action sentence, given the coin flip before it,
+// if any.
+func lowerSentence(sentence Sentence, flip *Flip) []core.Effect {
+	var lowered []core.Effect
+	switch action := sentence.Action.(type) {
+	case *DamageAction:
+		lowered = []core.Effect{lowerDamage(action, flip)}
+	case *HealAction:
+		lowered = []core.Effect{{Type: core.EffectHeal, Target: lowerTarget(action.From), Amount: action.Amount}}
+	case *DrawAction:
+		lowered = []core.Effect{{Type: core.EffectDraw, Amount: action.Count}}
+	case *StatusAction:
+		lowered = lowerStatus(action, sentence.Condition)
+	case *DiscardEnergyAction:
+		lowered = lowerDiscardEnergy(action)
+	}
+
+	for i := range lowered {
+		lowered[i].Description = sentence.Text
+		lowerCondition(&lowered[i], sentence.Condition, flip)
+		if lowered[i].Conditions != nil && *lowered[i].Conditions == (core.Conditions{}) {
+			lowered[i].Conditions = nil
+		}
+	}
+	return lowered
+}
+
+func lowerDamage(action *DamageAction, flip *Flip) core.Effect {
+	effect := core.Effect{Type: core.EffectDamage, Target: core.TargetOpponentActive, Amount: action.Amount}
+	switch {
+	case action.ToSelf:
+		effect.Type, effect.Target = core.EffectRecoilDamage, core.TargetSelf
+	case action.To != nil:
+		lowerDamageTarget(&effect, action)
+	case action.More:
+		effect.Type, effect.Target = core.EffectConditionalDamage, ""
+	default:
+		effect.Target = core.TargetOpponentActive
+	}
+	if action.Per == nil {
+		return effect
+	}
+
+	conditions := conditionsOf(&effect)
+	if action.Per.Count == CountHeads {
+		// Heads are counted from the flip before, which says how many
+		// coins there are.
+		effect.Type, effect.Target = core.EffectScalingDamage, ""
+		conditions.Scaling = &core.Scaling{By: "COIN_FLIP_HEADS"}
+		if flip != nil && flip.UntilTails {
+			conditions.Scaling.By = "COIN_FLIP_HEADS_UNTIL_TAILS"
+			if action.More {
+				effect.Type = core.EffectConditionalDamage
+			}
+		}
+		lowerFlipCount(conditions, flip)
+		return effect
+	}
+	if effect.Type == core.EffectDamage || effect.Type == core.EffectConditionalDamage {
+		effect.Type, effect.Target = core.EffectScalingDamage, ""
+	}
+	conditions.Scaling = lowerQuantity(action.Per)
+	conditions.Scaling.Base = !action.More
+	return effect
+}
+
+// lowerDamageTarget sets the type and target of damage done to a Pokémon
+// other than the opponent's Active Pokémon.
+func lowerDamageTarget(effect *core.Effect, action *DamageAction) {
+	switch action.To.Who {
+	case WhoOneOpponent:
+		effect.Type, effect.Target = core.EffectSnipeDamage, ""
+		conditionsOf(effect).Filter = &core.Filter{Pool: "ANY_OPPONENT", Type: action.To.Type}
+	case WhoOneOpponentBenched:
+		effect.Type, effect.Target = core.EffectSnipeDamage, core.TargetBenchedOpponent
+	case WhoEachOpponent:
+		effect.Type, effect.Target = core.EffectDamageAllOpponent, ""
+	case WhoEachOpponentBenched:
+		effect.Type, effect.Target = core.EffectDamageBenchedOpponentAll, core.TargetBenchedOpponentAll
+	case WhoOneYours, WhoOneYourBenched, WhoEachYours, WhoEachYourBenched:
+		effect.Type, effect.Target = core.EffectDamageBenchedFriendly, core.TargetBenchedFriendly
+		filter := &core.Filter{Type: action.To.Type}
+		switch action.To.Who {
+		case WhoOneYours:
+			effect.Target = ""
+			filter.Pool = "ANY_FRIENDLY"
+		case WhoEachYours:
+			filter.Pool, filter.All = "ANY_FRIENDLY", true
+		case WhoEachYourBenched:
+			filter.All = true
+		}
+		if filter.Pool != "" || filter.All || filter.Type != "" {
+			conditionsOf(effect).Filter = filter
+		}
+	case WhoThisPokemon:
+		effect.Type, effect.Target = core.EffectRecoilDamage, core.TargetSelf
+	}
+}
+
+// lowerStatus gives a Special Condition to each Pokémon the action targets.
+// Unconditional statuses are one effect each; statuses that depend on a
+// coin flip are listed in a single effect's modifier.
+func lowerStatus(action *StatusAction, condition Condition) []core.Effect {
+	targets := []core.TargetType{lowerTarget(action.Target)}
+	if action.Target.Who == WhoBothActive {
+		targets = []core.TargetType{core.TargetSelf, core.TargetOpponentActive}
+	}
+	var lowered []core.Effect
+	for _, target := range targets {
+		if _, onCoin := condition.(*CoinCondition); onCoin {
+			lowered = append(lowered, core.Effect{
+				Type:       core.EffectApplyStatus,
+				Target:     target,
+				Conditions: &core.Conditions{Modifier: &core.Modifier{Statuses: action.Statuses}},
+			})
+			continue
+		}
+		for _, status := range action.Statuses {
+			lowered = append(lowered, core.Effect{Type: core.EffectApplyStatus, Target: target, Status: status})
+		}
+	}
+	return lowered
+}
+
+func lowerDiscardEnergy(action *DiscardEnergyAction) []core.Effect {
+	targets := []core.TargetType{lowerTarget(action.From)}
+	if action.From.Who == WhoBothActive {
+		targets = []core.TargetType{core.TargetSelf, core.TargetOpponentActive}
+	}
+	var lowered []core.Effect
+	for _, target := range targets {
+		effect := core.Effect{Type: core.EffectDiscardEnergy, Target: target, Amount: action.Count}
+		conditions := &core.Conditions{}
+		switch len(action.Types) {
+		case 0:
+		case 1:
+			conditions.Energy = &core.Energy{Type: action.Types[0]}
+		default:
+			conditions.Energy = &core.Energy{Types: action.Types}
+		}
+		if action.All || action.Random {
+			conditions.Modifier = &core.Modifier{All: action.All, Random: action.Random}
+		}
+		effect.Conditions = conditions
+		lowered = append(lowered, effect)
+	}
+	return lowered
+}
+
+// lowerCondition adds what the effect depends on to its conditions.
+func lowerCondition(effect *core.Effect, condition Condition, flip *Flip) {
+	switch condition := condition.(type) {
+	case *CoinCondition:
+		conditions := conditionsOf(effect)
+		switch {
+		case condition.ForEachHeads:
+			conditions.Scaling = &core.Scaling{By: "COIN_FLIP_HEADS"}
+			if flip != nil && flip.UntilTails {
+				conditions.Scaling.By = "COIN_FLIP_HEADS_UNTIL_TAILS"
+			}
+			lowerFlipCount(conditions, flip)
+		case condition.Result == core.CoinHeads && effect.Type == core.EffectConditionalDamage:
+			// "If heads, this attack does N more damage" scales the extra
+			// damage by the one coin.
+			conditions.Scaling = &core.Scaling{By: "COIN_FLIP_HEADS"}
+		default:
+			conditions.CoinFlip = &core.CoinFlip{Result: condition.Result}
+		}
+	case *StateCondition:
+		trigger, requirement := lowerState(condition)
+		conditions := conditionsOf(effect)
+		if requirement != nil {
+			conditions.Requirement = requirement
+			return
+		}
+		conditions.Trigger = trigger
+		if condition.State.Kind == StateHasEnergy {
+			conditions.Energy = &core.Energy{Type: condition.State.Type}
+		}
+		if effect.Type == core.EffectDamage {
+			effect.Type, effect.Target = core.EffectConditionalDamage, ""
+		}
+	}
+}
+
+// lowerFlipCount records how many coins the flip before an effect flips,
+// when that isn't 1.
+func lowerFlipCount(conditions *core.Conditions, flip *Flip) {
+	if flip == nil {
+		return
+	}
+	switch {
+	case flip.Coins > 1:
+		conditions.CoinFlip = &core.CoinFlip{Flips: flip.Coins}
+	case flip.Per != nil:
+		conditions.CoinFlip = &core.CoinFlip{FlipsPer: lowerFlipsPer(flip.Per)}
+		if flip.Per.Type != "" {
+			conditions.Energy = &core.Energy{Type: flip.Per.Type}
+		}
+	}
+}
+
+func lowerFlipsPer(q *Quantity) string {
+	switch {
+	case q.Count == CountPokemon && q.Of.Who == WhoYours:
+		return "ALL_POKEMON_IN_PLAY"
+	case q.Count == CountEnergy && q.Type != "":
+		return "SELF_ATTACHED_ENERGY_TYPED"
+	}
+	return "SELF_ATTACHED_ENERGY"
+}
+
+// lowerState turns a condition on the game state into the trigger that
+// checks it, or, for Energy an attack needs beyond its cost, a requirement.
+func lowerState(condition *StateCondition) (*core.Trigger, *core.Requirement) {
+	state := condition.State
+	opponent := condition.Subject.Who == WhoOpponentActive
+	event := func(self, opp core.TriggerEvent) *core.Trigger {
+		if opponent {
+			return &core.Trigger{Event: opp}
+		}
+		return &core.Trigger{Event: self}
+	}
+	switch state.Kind {
+	case StateHasDamage:
+		if condition.Subject.Who == WhoAnyYourBenched {
+			return &core.Trigger{Event: core.TriggerAnyBenchedFriendlyHasDamage}, nil
+		}
+		return event(core.TriggerSelfHasDamage, core.TriggerOpponentHasDamage), nil
+	case StateHasNoDamage:
+		return &core.Trigger{Event: core.TriggerSelfHasNoDamage}, nil
+	case StateHasTool:
+		return event(core.TriggerSelfHasTool, core.TriggerOpponentHasTool), nil
+	case StateHasAbility:
+		return &core.Trigger{Event: core.TriggerOpponentHasAbility}, nil
+	case StateHasEnergy:
+		return &core.Trigger{Event: core.TriggerSelfHasTypedEnergy}, nil
+	case StateHasExtraEnergy:
+		return nil, &core.Requirement{EnergyType: state.Type, ExtraEnergy: state.Count}
+	case StateDifferentEnergyTypes:
+		return &core.Trigger{Event: core.TriggerDifferentEnergyTypesAttached, Count: state.Count}, nil
+	case StateMoreRemainingHP:
+		return &core.Trigger{Event: core.TriggerOpponentHPGreater}, nil
+	case StateStatus:
+		return &core.Trigger{Event: core.TriggerOpponentHasStatus, Status: state.Status}, nil
+	case StateSpecialCondition:
+		return &core.Trigger{Event: core.TriggerOpponentHasSpecialCondition}, nil
+	case StateStage:
+		return &core.Trigger{Event: core.TriggerOpponentIsStage, Stage: state.Stage}, nil
+	case StateProperty:
+		if state.Text == "a Pokémon {ex}" {
+			return &core.Trigger{Event: core.TriggerOpponentIsEX}, nil
+		}
+		return &core.Trigger{Event: core.TriggerOpponentHasProperty, Property: strings.ToUpper(state.Text)}, nil
+	case StateNamed:
+		return &core.Trigger{Event: core.TriggerOpponentIsName, Name: state.Name}, nil
+	case StateOnBench:
+		return &core.Trigger{Event: core.TriggerPokemonOnBench, Name: condition.Subject.Names[0]}, nil
+	case StateEvolvedThisTurn:
+		return &core.Trigger{Event: core.TriggerEvolvedThisTurn}, nil
+	case StateMovedToActive:
+		return &core.Trigger{Event: core.TriggerSwitchedInThisTurn}, nil
+	case StatePlayedSupporter:
+		return &core.Trigger{Event: core.TriggerPlayedSupporterThisTurn}, nil
+	}
+	return nil, nil
+}
+
+// lowerQuantity turns what "for each" counts into a scaling.
+func lowerQuantity(q *Quantity) *core.Scaling {
+	switch q.Count {
+	case CountEnergy:
+		switch q.Of.Who {
+		case WhoOpponentActive:
+			return &core.Scaling{By: "OPPONENT_ATTACHED_ENERGY", Type: q.Type}
+		case WhoEachOpponent:
+			return &core.Scaling{By: "ALL_OPPONENT_POKEMON_ENERGY", Type: q.Type}
+		}
+		return &core.Scaling{By: "SELF_ATTACHED_ENERGY", Type: q.Type}
+	case CountRetreatCost:
+		return &core.Scaling{By: "OPPONENT_RETREAT_COST"}
+	}
+
+	of := q.Of
+	switch {
+	case of.Who == WhoEachBenched:
+		return &core.Scaling{By: "ALL_BENCHED_POKEMON_COUNT"}
+	case of.Who == WhoEvolutionYourBenched:
+		return &core.Scaling{By: "BENCHED_POKEMON_TYPE", Evolved: true}
+	case of.Who == WhoEachOpponentBenched:
+		return &core.Scaling{By: "OPPONENT_BENCHED_POKEMON_COUNT", Type: of.Type}
+	case len(of.Names) == 1:
+		return &core.Scaling{By: "BENCHED_POKEMON_NAME", Names: of.Names}
+	case len(of.Names) > 1:
+		return &core.Scaling{By: "BENCHED_POKEMON_NAMES", Names: of.Names}
+	case of.Type != "":
+		return &core.Scaling{By: "BENCHED_POKEMON_TYPE_COUNT", Type: of.Type}
+	}
+	return &core.Scaling{By: "BENCHED_POKEMON_COUNT"}
+}
+
+// lowerTarget turns the target of a heal, status or discard into the
+// effect's target.
+func lowerTarget(target Target) core.TargetType {
+	switch target.Who {
+	case WhoThisPokemon, WhoYourActive:
+		return core.TargetSelf
+	case WhoEachYours:
+		return core.TargetAllFriendly
+	case WhoOneYourBenched, WhoEachYourBenched, WhoAnyYourBenched:
+		return core.TargetBenchedFriendly
+	case WhoOneOpponentBenched, WhoOneOpponent:
+		return core.TargetBenchedOpponent
+	case WhoEachOpponentBenched:
+		return core.TargetBenchedOpponentAll
+	}
+	return core.TargetOpponentActive
+}
+
+// conditionsOf returns the effect's conditions, adding them if it has none.
+func conditionsOf(effect *core.Effect) *core.Conditions {
+	if effect.Conditions == nil {
+		effect.Conditions = &core.Conditions{}
+	}
+	return effect.Conditions
+}
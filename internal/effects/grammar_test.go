@@ -0,0 +1,106 @@
+package effects
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+func TestParseGrammarAgreesWithRules(t *testing.T) {
+	texts := []string{
+		"Flip a coin. If heads, this attack does 40 more damage. If tails, this Pokémon also does 20 damage to itself.",
+		"Flip 2 coins. This attack does 30 damage for each heads.",
+		"Flip a coin until you get tails. This attack does 30 more damage for each heads.",
+		"Flip a coin for each {M} Energy attached to this Pokémon. This attack does 50 damage for each heads.",
+		"Flip 2 coins. If both of them are heads, this attack does 80 more damage.",
+		"Flip a coin. If heads, your opponent's Active Pokémon is now Poisoned and Paralyzed.",
+		"Your opponent's Active Pokémon is now Poisoned and Burned.",
+		"Both Active Pokémon are now Asleep.",
+		"Discard all {L} Energy from this Pokémon. This attack does 120 damage to 1 of your opponent's Pokémon.",
+		"Discard a random Energy from both Active Pokémon.",
+		"This attack also does 20 damage to each of your Benched Pokémon.",
+		"This attack does 20 more damage for each of your Benched Wishiwashi and Wishiwashi ex.",
+		"If your opponent's Active Pokémon is a Basic Pokémon, this attack does 60 more damage.",
+		"If Latios is on your Bench, this attack does 20 more damage.",
+		"Heal 50 damage from 1 of your Benched Pokémon.",
+	}
+	for _, text := range texts {
+		ast, err := ParseGrammar(text)
+		if err != nil {
+			t.Errorf("ParseGrammar(%q): %v", text, err)
+			continue
+		}
+		got, want := comparableEffects(ast.Effects()), comparableEffects(ParseText(text).Effects)
+		if differences := effectDifferences(got, want); len(differences) > 0 {
+			t.Errorf("ParseGrammar(%q) differs from the rules in %v:\n got  %+v\n want %+v", text, differences, got, want)
+		}
+	}
+}
+
+func TestParseGrammarCombinesPhrases(t *testing.T) {
+	// No rule combines a condition with scaling damage, but the grammar
+	// parses any condition before any action.
+	text := "If your opponent's Active Pokémon is Poisoned, this attack does 20 damage for each of your Benched Pokémon."
+	if result := ParseText(text); len(result.Unparsed) == 0 && result.Effects[0].Type != core.EffectUnknown {
+		t.Fatalf("the rules parse %q, so it doesn't test the grammar", text)
+	}
+	ast, err := ParseGrammar(text)
+	if err != nil {
+		t.Fatalf("ParseGrammar(%q): %v", text, err)
+	}
+	want := []core.Effect{{
+		Type:   core.EffectScalingDamage,
+		Amount: 20,
+		Conditions: &core.Conditions{
+			Trigger: &core.Trigger{Event: core.TriggerOpponentHasStatus, Status: core.StatusPoisoned},
+			Scaling: &core.Scaling{By: "BENCHED_POKEMON_COUNT", Base: true},
+		},
+		Description: text,
+	}}
+	if got := ast.Effects(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseGrammar(%q).Effects() = %+v, want %+v", text, got, want)
+	}
+}
+
+func TestParseGrammarError(t *testing.T) {
+	tests := []struct {
+		text string
+		want GrammarError
+	}{
+		{"Flip a coin. If heads, this attack does lots of damage.",
+			GrammarError{Offset: 40, Found: "lots", Expected: []string{"a number"}}},
+		{"Flip a coin. If heads, your opponent's Active Pokémon is now Sleepy.",
+			GrammarError{Offset: 62, Found: "Sleepy", Expected: []string{"a Special Condition"}}},
+		{"Shuffle your hand into your deck.",
+			GrammarError{Offset: 0, Found: "Shuffle", Expected: []string{"a coin flip, a condition or an action"}}},
+		{"Draw a card",
+			GrammarError{Offset: 11, Expected: []string{`"."`}}},
+	}
+	for _, tt := range tests {
+		_, err := ParseGrammar(tt.text)
+		var got *GrammarError
+		if !errors.As(err, &got) {
+			t.Errorf("ParseGrammar(%q) error = %v, want a GrammarError", tt.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("ParseGrammar(%q) error = %v, want %v", tt.text, got, &tt.want)
+		}
+	}
+}
+
+func TestCompareGrammarTrainerText(t *testing.T) {
+	// Trainer card text is parsed by the trainer rules, as ParseTrainer does.
+	card := tcgdex.Card{Name: "Professor's Research", Category: "Trainer", Text: "Draw 2 cards."}
+	comparison := CompareGrammar([]tcgdex.Card{card})
+	if comparison.Texts != 1 || len(comparison.Results) != 1 {
+		t.Fatalf("CompareGrammar compared %d texts with results %+v, want the Trainer text", comparison.Texts, comparison.Results)
+	}
+	result := comparison.Results[0]
+	if len(result.Rules) != 1 || result.Rules[0].Type != core.EffectDraw || result.Rules[0].Target != core.TargetDeck {
+		t.Errorf("rules parse = %+v, want the trainer rule's DRAW from the deck", result.Rules)
+	}
+}
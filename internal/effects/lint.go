@@ -0,0 +1,179 @@
+package effects
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// LintReport describes how a rule table behaves on a body of effect texts.
+type LintReport struct {
+	Texts int `json:"texts"` // The number of distinct texts and clauses checked
+
+	// Ambiguous lists the texts more than one rule matches.
+	Ambiguous []AmbiguousMatch `json:"ambiguous"`
+	// Unused lists the rules that match none of the texts.
+	Unused []string `json:"unused"`
+	// Shadowed lists the rules that match some texts, but only ever after an
+	// earlier rule has matched them first.
+	Shadowed []ShadowedRule `json:"shadowed"`
+}
+
+// AmbiguousMatch is a text that several rules match.
+type AmbiguousMatch struct {
+	Text string `json:"text"`
+	// Rules lists the rules that match, in the order they're tried; the first
+	// is the one the parser uses.
+	Rules []string `json:"rules"`
+	// Conflicting is true if any of the later rules would have parsed the
+	// text differently, rather than the rules merely overlapping.
+	Conflicting bool `json:"conflicting"`
+}
+
+// ShadowedRule is a rule that never gets to parse anything.
+type ShadowedRule struct {
+	Rule string `json:"rule"`
+	// By lists the rules that match first, in the order they're tried.
+	By []string `json:"by"`
+	// Example is one of the texts the rule matches.
+	Example string `json:"example"`
+}
+
+// Problems reports whether the report shows rules that can never take
+// effect, being either unused or shadowed. Ambiguous matches alone are not a
+// problem: specific rules are meant to be tried before the general ones they
+// refine.
+func (r *LintReport) Problems() bool {
+	return len(r.Unused) > 0 || len(r.Shadowed) > 0
+}
+
+// EffectTexts returns the text of every ability and attack effect in cards,
+// and the text of every Trainer card, which together are everything
+// ParseCard parses.
+func EffectTexts(cards []tcgdex.Card) (texts, trainerTexts []string) {
+	for _, card := range cards {
+		for _, ability := range card.Abilities {
+			texts = append(texts, ability.Effect)
+		}
+		for _, attack := range card.Attacks {
+			texts = append(texts, attack.Effect)
+		}
+		if card.IsTrainer() {
+			trainerTexts = append(trainerTexts, card.Text)
+		}
+	}
+	return texts, trainerTexts
+}
+
+// lintInput is a text to lint and the scope of the rules that parse it.
+type lintInput struct {
+	scope, text string
+}
+
+// Lint runs every rule against every text, and against each clause of the
+// texts, since compound texts are parsed a clause at a time. Trainer card
+// texts are also run against the trainer rules, a "Choose 1:" option at a
+// time, as ParseTrainer tries them first. Texts are normalised first, as they
+// are for parsing, so the report quotes them in their normalised form.
+func (rs *RuleSet) Lint(texts, trainerTexts []string) *LintReport {
+	seen := make(map[lintInput]bool)
+	var inputs []lintInput
+	add := func(scope, text string) {
+		input := lintInput{scope, strings.TrimSpace(text)}
+		if input.text != "" && !seen[input] {
+			seen[input] = true
+			inputs = append(inputs, input)
+		}
+	}
+	for _, text := range slices.Concat(texts, trainerTexts) {
+		text = Normalize(text).Text
+		add("", text)
+		clauses := SplitClauses(text)
+		for _, clause := range clauses {
+			add("", clause.Text)
+			// A dependent clause is also parsed with the one it depends on.
+			if clause.DependsOn >= 0 {
+				add("", clauses[clause.DependsOn].Text+" "+clause.Text)
+			}
+		}
+	}
+	for _, text := range trainerTexts {
+		for _, body := range trainerBodies(Normalize(strings.TrimSpace(text)).Text) {
+			add(ScopeTrainer, body)
+		}
+	}
+	slices.SortFunc(inputs, func(a, b lintInput) int {
+		if c := strings.Compare(a.text, b.text); c != 0 {
+			return c
+		}
+		return strings.Compare(a.scope, b.scope)
+	})
+
+	report := &LintReport{Texts: len(inputs)}
+	wins := make(map[*Rule]bool)
+	matched := make(map[*Rule]string) // Rule to the first text it matches
+	before := make(map[*Rule][]*Rule) // Rule to the rules that beat it to a text
+	for _, input := range inputs {
+		text := input.text
+		var matching []*Rule
+		var first []core.Effect
+		conflicting := false
+		for _, rule := range rs.rules {
+			if rule.Scope != input.scope {
+				continue
+			}
+			parsed, ok := rule.apply(text)
+			if !ok {
+				continue
+			}
+			if _, ok := matched[rule]; !ok {
+				matched[rule] = text
+			}
+			if len(matching) == 0 {
+				wins[rule] = true
+				first = parsed
+			} else {
+				if !slices.Contains(before[rule], matching[0]) {
+					before[rule] = append(before[rule], matching[0])
+				}
+				conflicting = conflicting || !sameEffects(parsed, first)
+			}
+			matching = append(matching, rule)
+		}
+		if len(matching) > 1 {
+			report.Ambiguous = append(report.Ambiguous, AmbiguousMatch{
+				Text:        text,
+				Rules:       ruleNames(matching),
+				Conflicting: conflicting,
+			})
+		}
+	}
+
+	for _, rule := range rs.rules {
+		example, ok := matched[rule]
+		switch {
+		case !ok:
+			report.Unused = append(report.Unused, rule.Name)
+		case !wins[rule]:
+			by := before[rule]
+			slices.SortFunc(by, func(a, b *Rule) int { return rs.index(a) - rs.index(b) })
+			report.Shadowed = append(report.Shadowed, ShadowedRule{Rule: rule.Name, By: ruleNames(by), Example: example})
+		}
+	}
+	return report
+}
+
+// index returns the position of rule in the order rules are tried.
+func (rs *RuleSet) index(rule *Rule) int {
+	return slices.Index(rs.rules, rule)
+}
+
+func ruleNames(rules []*Rule) []string {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = rule.Name
+	}
+	return names
+}
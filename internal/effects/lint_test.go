@@ -0,0 +1,78 @@
+package effects
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/pkg/tcgdex/tcgdextest"
+)
+
+// TestDefaultRulesLint guards the built-in rule table against rules that
+// can't take effect on the cards we have, such as a specific rule listed
+// after a broader one that matches the same text. Run `genomon parser lint`
+// for the full report.
+func TestDefaultRulesLint(t *testing.T) {
+	cards, err := tcgdextest.LoadFixtures("../../ptcgp-cards.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := DefaultRules().Lint(EffectTexts(cards))
+	for _, name := range report.Unused {
+		t.Errorf("rule %q matches no card", name)
+	}
+	for _, shadowed := range report.Shadowed {
+		t.Errorf("rule %q is shadowed by %q, e.g. on %q", shadowed.Rule, shadowed.By, shadowed.Example)
+	}
+}
+
+func TestLintFindsShadowedRules(t *testing.T) {
+	rs, err := LoadRules([]byte(`{"rules": [
+		{"name": "any heal", "pattern": "Heal (\\d+) damage", "effects": [{"type": "HEAL", "amount": "$1|int"}]},
+		{"name": "heal self", "pattern": "Heal (\\d+) damage from this Pokémon", "effects": [{"type": "HEAL", "target": "SELF", "amount": "$1|int"}]},
+		{"name": "heal all", "pattern": "Heal (\\d+) damage from each", "priority": 1, "effects": [{"type": "HEAL", "amount": "$1|int"}]},
+		{"name": "draw", "pattern": "Draw a card", "effects": [{"type": "DRAW"}]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := rs.Lint([]string{"Heal 20 damage from this Pokémon.", "Heal 10 damage from each of your Pokémon."}, nil)
+
+	if want := []string{"draw"}; !reflect.DeepEqual(report.Unused, want) {
+		t.Errorf("Unused = %q, want %q", report.Unused, want)
+	}
+	wantShadowed := []ShadowedRule{{Rule: "heal self", By: []string{"any heal"}, Example: "Heal 20 damage from this Pokémon."}}
+	if !reflect.DeepEqual(report.Shadowed, wantShadowed) {
+		t.Errorf("Shadowed = %+v, want %+v", report.Shadowed, wantShadowed)
+	}
+	wantAmbiguous := []AmbiguousMatch{
+		{Text: "Heal 10 damage from each of your Pokémon.", Rules: []string{"heal all", "any heal"}, Conflicting: false},
+		{Text: "Heal 20 damage from this Pokémon.", Rules: []string{"any heal", "heal self"}, Conflicting: true},
+	}
+	if !reflect.DeepEqual(report.Ambiguous, wantAmbiguous) {
+		t.Errorf("Ambiguous = %+v, want %+v", report.Ambiguous, wantAmbiguous)
+	}
+	if !report.Problems() {
+		t.Error("Problems() = false, want true")
+	}
+}
+
+func TestLintTrainerScope(t *testing.T) {
+	rs, err := LoadRules([]byte(`{"rules": [
+		{"name": "draw", "pattern": "Draw (\\d+) cards", "effects": [{"type": "DRAW", "amount": "$1|int"}]},
+		{"name": "trainer draw", "scope": "trainer", "pattern": "^Draw (\\d+) cards\\.$", "effects": [{"type": "DRAW", "target": "DECK", "amount": "$1|int"}]},
+		{"name": "trainer heal", "scope": "trainer", "pattern": "^Heal (\\d+) damage", "effects": [{"type": "HEAL", "amount": "$1|int"}]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The trainer rules don't compete with the others, and only see
+	// Trainer card text, with any usage requirement removed.
+	report := rs.Lint([]string{"Heal 20 damage from this Pokémon."}, []string{"You can use this card only if you have 2 points.\n\nDraw 2 cards."})
+	if want := []string{"trainer heal"}; !reflect.DeepEqual(report.Unused, want) {
+		t.Errorf("Unused = %q, want %q", report.Unused, want)
+	}
+	if len(report.Shadowed) != 0 || len(report.Ambiguous) != 0 {
+		t.Errorf("Shadowed = %+v, Ambiguous = %+v, want none", report.Shadowed, report.Ambiguous)
+	}
+}
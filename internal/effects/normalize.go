@@ -0,0 +1,153 @@
+package effects
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Normalized is effect text in the one spelling the rules are written for,
+// together with where each part of it came from in the original text.
+type Normalized struct {
+	Text     string
+	Original string
+	// offsets[i] is the byte offset in Original that byte i of Text came
+	// from. Text written in place of part of the original comes from its
+	// start, except that a space comes from the end of the whitespace it
+	// replaces, so that a clause's span takes in any reminder text after it.
+	offsets []int
+}
+
+// Normalize canonicalises the typography of effect text, so that rules need
+// only match one spelling of each phrase:
+//
+//   - curly quotes and apostrophes become straight ones;
+//   - a hyphen or dash used as a minus sign, as in "-20", becomes "−";
+//   - "Pokemon" is spelled "Pokémon";
+//   - reminder text, a parenthesised sentence such as "(Your opponent chooses
+//     the new Active Pokémon.)", is dropped, since it explains a rule rather
+//     than adding to the effect;
+//   - runs of whitespace become a single space, or a blank line if they
+//     contain one, since some cards set out options as paragraphs;
+//   - leading and trailing whitespace is trimmed.
+func Normalize(text string) Normalized {
+	n := Normalized{Text: text, Original: text, offsets: make([]int, len(text))}
+	for i := range n.offsets {
+		n.offsets[i] = i
+	}
+	n.replace(reminderTextRegex, 0, false, func(string) string { return "" })
+	n.replace(curlyQuoteRegex, 0, false, func(quote string) string {
+		if quote == "’" || quote == "‘" {
+			return "'"
+		}
+		return `"`
+	})
+	n.replace(minusSignRegex, 1, false, func(string) string { return "−" })
+	n.replace(pokemonSpellingRegex, 0, false, func(word string) string {
+		if word == strings.ToUpper(word) {
+			return "POKÉMON"
+		}
+		return word[:3] + "é" + word[4:]
+	})
+	n.replace(whitespaceRegex, 0, true, func(space string) string {
+		if strings.Count(space, "\n") > 1 {
+			return "\n\n"
+		}
+		return " "
+	})
+	n.trim()
+	return n
+}
+
+var (
+	reminderTextRegex    = regexp.MustCompile(`\(\p{Lu}[^()]*[.!]\)`)
+	curlyQuoteRegex      = regexp.MustCompile(`[‘’“”]`)
+	minusSignRegex       = regexp.MustCompile(`(?:^|[\s(])([-‐‑–])\d`)
+	pokemonSpellingRegex = regexp.MustCompile(`\b(?:Pokemon|pokemon|POKEMON)\b`)
+	whitespaceRegex      = regexp.MustCompile(`\s+`)
+)
+
+// replace rewrites the given submatch of every match of re with the result
+// of repl, keeping track of where each byte came from: the start of the
+// submatch, or its last byte if fromEnd is set.
+func (n *Normalized) replace(re *regexp.Regexp, submatch int, fromEnd bool, repl func(string) string) {
+	matches := re.FindAllStringSubmatchIndex(n.Text, -1)
+	if len(matches) == 0 {
+		return
+	}
+	var b strings.Builder
+	offsets := make([]int, 0, len(n.offsets))
+	last := 0
+	for _, match := range matches {
+		start, end := match[2*submatch], match[2*submatch+1]
+		if start < 0 {
+			continue
+		}
+		b.WriteString(n.Text[last:start])
+		offsets = append(offsets, n.offsets[last:start]...)
+		replacement := repl(n.Text[start:end])
+		b.WriteString(replacement)
+		from := n.offsets[start]
+		if fromEnd {
+			from = n.offsets[end-1]
+		}
+		for range len(replacement) {
+			offsets = append(offsets, from)
+		}
+		last = end
+	}
+	b.WriteString(n.Text[last:])
+	n.Text = b.String()
+	n.offsets = append(offsets, n.offsets[last:]...)
+}
+
+// trim removes leading and trailing whitespace, which the whitespace pass
+// leaves as a single space or blank line.
+func (n *Normalized) trim() {
+	trimmed := strings.TrimLeft(n.Text, " \n")
+	start := len(n.Text) - len(trimmed)
+	trimmed = strings.TrimRight(trimmed, " \n")
+	n.Text = trimmed
+	n.offsets = n.offsets[start : start+len(trimmed)]
+}
+
+// Span returns the original text that Text[start:end] was normalised from,
+// including any reminder text dropped from within it.
+func (n Normalized) Span(start, end int) string {
+	from, to := n.bounds(start, end)
+	return n.Original[from:to]
+}
+
+// Locate returns the byte offsets in Original of the text that s, a part of
+// Text, was normalised from, or false if s isn't part of Text.
+func (n Normalized) Locate(s string) (start, end int, ok bool) {
+	at := strings.Index(n.Text, s)
+	if s == "" || at < 0 {
+		return 0, 0, false
+	}
+	start, end = n.bounds(at, at+len(s))
+	return start, end, true
+}
+
+// Restore returns the original text that s, a part of Text such as a clause,
+// was normalised from. Text that isn't part of Text, such as clauses joined
+// across a paragraph break, is returned as it is.
+func (n Normalized) Restore(s string) string {
+	start, end, ok := n.Locate(s)
+	if !ok {
+		return s
+	}
+	return n.Original[start:end]
+}
+
+// bounds returns the byte offsets in Original of Text[start:end], without
+// trailing whitespace.
+func (n Normalized) bounds(start, end int) (int, int) {
+	if start >= end {
+		return 0, 0
+	}
+	from, to := n.offsets[start], len(n.Original)
+	if end < len(n.offsets) {
+		to = n.offsets[end]
+	}
+	return from, from + len(strings.TrimRight(n.Original[from:to], " \t\r\n"))
+}
@@ -0,0 +1,73 @@
+package effects
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"Switch out your opponent’s Active Pokémon to the Bench. (Your opponent chooses the new Active Pokémon.)",
+			"Switch out your opponent's Active Pokémon to the Bench."},
+		{"This Pokémon takes -20 damage from attacks.", "This Pokémon takes −20 damage from attacks."},
+		{"This Pokémon takes –20 damage from attacks.", "This Pokémon takes −20 damage from attacks."},
+		{"Attach a Basic Energy to 1 of your Benched  Pokemon.", "Attach a Basic Energy to 1 of your Benched Pokémon."},
+		{"Play this card as if it were a 40-HP Basic {C} Pokémon.\nThis card can't retreat.",
+			"Play this card as if it were a 40-HP Basic {C} Pokémon. This card can't retreat."},
+		{"Choose 1:\n\nHeal 20 damage.\n \nDraw a card.\n", "Choose 1:\n\nHeal 20 damage.\n\nDraw a card."},
+		// Parentheses within a sentence are part of it.
+		{"Heal 20 damage from each Pokémon (both yours and your opponent's).",
+			"Heal 20 damage from each Pokémon (both yours and your opponent's)."},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.text).Text; got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizedRestore(t *testing.T) {
+	text := "Flip a coin. If heads, switch out your opponent’s  Active Pokémon to the Bench. (Your opponent chooses the new Active Pokémon.) Heal 10 damage from this Pokemon."
+	n := Normalize(text)
+	tests := []struct {
+		part, want string
+	}{
+		{"Flip a coin.", "Flip a coin."},
+		{"If heads, switch out your opponent's Active Pokémon to the Bench.",
+			"If heads, switch out your opponent’s  Active Pokémon to the Bench. (Your opponent chooses the new Active Pokémon.)"},
+		{"Heal 10 damage from this Pokémon.", "Heal 10 damage from this Pokemon."},
+		{n.Text, text},
+		{"Not from the text.", "Not from the text."},
+	}
+	for _, tt := range tests {
+		if got := n.Restore(tt.part); got != tt.want {
+			t.Errorf("Restore(%q) = %q, want %q", tt.part, got, tt.want)
+		}
+	}
+}
+
+func TestParseTextDescribesOriginalText(t *testing.T) {
+	text := "This Pokémon takes -20 damage from attacks. Heal 10 damage from this Pokemon."
+	result := ParseText(text)
+	if len(result.Effects) != 2 {
+		t.Fatalf("ParseText(%q) = %d effects, want 2", text, len(result.Effects))
+	}
+	if got := result.Effects[0].Conditions.Modifier.Amount; got != 20 {
+		t.Errorf("reduction = %d, want 20", got)
+	}
+	if got, want := result.Effects[1].Type, core.EffectHeal; got != want {
+		t.Errorf("second effect = %s, want %s", got, want)
+	}
+	if got, want := result.Effects[1].Description, "Heal 10 damage from this Pokemon."; got != want {
+		t.Errorf("description = %q, want %q", got, want)
+	}
+
+	text = "Do a little  dance with this Pokemon."
+	if got := ParseText(text).Unparsed; !reflect.DeepEqual(got, []string{text}) {
+		t.Errorf("unparsed = %q, want %q", got, []string{text})
+	}
+}
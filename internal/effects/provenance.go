@@ -0,0 +1,85 @@
+package effects
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+// recordProvenance is whether Parse and the functions built on it fill in
+// each effect's provenance.
+var recordProvenance bool
+
+// SetProvenance sets whether Parse and the functions built on it record on
+// each effect the rule that produced it, the text the rule matched and the
+// parser Version. It is off by default.
+func SetProvenance(on bool) {
+	recordProvenance = on
+}
+
+// Version identifies the parser that parses effects: the VCS revision the
+// program was built from, if Go recorded one, and a digest of the rule table
+// in use, which changes with every edit to the rules.
+func Version() string {
+	return buildRevision() + "+rules." + rules.digest
+}
+
+// buildRevision returns the VCS revision the program was built from, marked
+// "-dirty" if it had uncommitted changes, or "devel" if it isn't known, as
+// under `go run`.
+var buildRevision = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	revision, dirty := "", false
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			dirty = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "devel"
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if dirty {
+		revision += "-dirty"
+	}
+	return revision
+})
+
+// newProvenance records that rule produced an effect from a pattern match.
+func newProvenance(rule string, matches []string) *core.Provenance {
+	provenance := &core.Provenance{Rule: rule, Text: matches[0]}
+	if len(matches) > 1 {
+		provenance.Captures = append([]string(nil), matches[1:]...)
+	}
+	return provenance
+}
+
+// attribute completes the provenance of effects parsed from n's text,
+// replacing the normalised text each rule matched with the original and
+// locating it there, or removes it if withProvenance isn't set.
+func (n Normalized) attribute(parsed []core.Effect, withProvenance bool) {
+	version := Version()
+	for i := range parsed {
+		if parsed[i].Provenance == nil || !withProvenance {
+			parsed[i].Provenance = nil
+			continue
+		}
+		provenance := *parsed[i].Provenance
+		provenance.Span = nil
+		if start, end, ok := n.Locate(provenance.Text); ok {
+			provenance.Text = n.Original[start:end]
+			provenance.Span = []int{start, end}
+		}
+		provenance.Version = version
+		parsed[i].Provenance = &provenance
+	}
+}
@@ -0,0 +1,95 @@
+package effects
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+func TestProvenance(t *testing.T) {
+	text := "Flip a coin. If heads, this attack does 40 more damage. If tails, this Pokémon also does 20 damage to itself."
+	for _, effect := range ParseText(text).Effects {
+		if effect.Provenance != nil {
+			t.Fatalf("provenance recorded without SetProvenance: %+v", effect.Provenance)
+		}
+	}
+
+	SetProvenance(true)
+	t.Cleanup(func() { SetProvenance(false) })
+	parsed := ParseText(text).Effects
+	if len(parsed) != 2 {
+		t.Fatalf("ParseText(%q) = %d effects, want 2", text, len(parsed))
+	}
+	recoil := parsed[1].Provenance
+	if recoil == nil {
+		t.Fatal("no provenance recorded")
+	}
+	if want := "If tails, this Pokémon also does 20 damage to itself."; recoil.Text != want {
+		t.Errorf("matched %q, want %q", recoil.Text, want)
+	}
+	if got := text[recoil.Span[0]:recoil.Span[1]]; got != recoil.Text {
+		t.Errorf("span %v of the text is %q, not the matched text", recoil.Span, got)
+	}
+	if !reflect.DeepEqual(recoil.Captures, []string{"20"}) {
+		t.Errorf("captures = %q, want [20]", recoil.Captures)
+	}
+	if !strings.Contains(recoil.Version, "+rules.") {
+		t.Errorf("version = %q, want the rule table's digest", recoil.Version)
+	}
+
+	// Trainer patterns are named after the effect they parse, and spans are
+	// in the original text.
+	trainer := ParseTrainer("Heal 50 damage from 1 of your {G} Pokemon.")
+	if got := trainer[0].Provenance; got == nil || got.Rule != "Trainer: HEAL (1 of your Pokémon, optionally recovering)" || got.Text != "Heal 50 damage from 1 of your {G} Pokemon." {
+		t.Errorf("trainer provenance = %+v", got)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	card := tcgdex.Card{
+		Name: "Electabuzz",
+		Attacks: []tcgdex.Attack{{
+			Name:   "Thunder Punch",
+			Effect: "Flip a coin. If heads, this attack does 40 more damage. If tails, this Pokémon also does 20 damage to itself.",
+		}},
+	}
+	explanations := Explain(card)
+	if len(explanations) != 1 {
+		t.Fatalf("Explain = %d texts, want 1", len(explanations))
+	}
+	explanation := explanations[0]
+	for _, effect := range explanation.Effects {
+		if effect.Name != "Thunder Punch" || effect.Provenance == nil {
+			t.Errorf("effect %s is named %q with provenance %v", effect.Type, effect.Name, effect.Provenance)
+		}
+	}
+	if len(explanation.Clauses) != 3 {
+		t.Fatalf("Explain found %d clauses, want 3", len(explanation.Clauses))
+	}
+	if got := explanation.Clauses[2].Rules; !reflect.DeepEqual(got, []string{"RECOIL DAMAGE (On Coin Flip)"}) {
+		t.Errorf("rules matching %q = %q", explanation.Clauses[2].Text, got)
+	}
+}
+
+func TestExplainTrainer(t *testing.T) {
+	card := tcgdex.Card{
+		Name:     "Erika",
+		Category: "Trainer",
+		Text:     "Heal 50 damage from 1 of your {G} Pokémon.",
+	}
+	explanations := Explain(card)
+	if len(explanations) != 1 || explanations[0].Kind != "trainer" {
+		t.Fatalf("Explain = %+v, want the Trainer text", explanations)
+	}
+	explanation := explanations[0]
+	rule := "Trainer: HEAL (1 of your Pokémon, optionally recovering)"
+	if len(explanation.Effects) != 1 || explanation.Effects[0].Provenance == nil || explanation.Effects[0].Provenance.Rule != rule {
+		t.Errorf("effects = %+v, want a HEAL from %q", explanation.Effects, rule)
+	}
+	want := ClauseRules{Text: card.Text, Scope: ScopeTrainer, Rules: []string{rule}}
+	if len(explanation.Clauses) == 0 || !reflect.DeepEqual(explanation.Clauses[0], want) {
+		t.Errorf("clauses = %+v, want %+v first", explanation.Clauses, want)
+	}
+}
@@ -0,0 +1,1176 @@
+package effects
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+// Render writes a parsed effect back out as English card text, built only
+// from its type, target, status, amount and conditions. Comparing the result
+// with the text the effect was parsed from shows what the parse kept; see
+// Verify. The description is never used, and UNKNOWN effects render as "".
+func Render(effect core.Effect) string {
+	if effect.Type == core.EffectUnknown {
+		return ""
+	}
+	c := effect.Conditions
+	if c == nil {
+		c = &core.Conditions{}
+	}
+
+	var clauses []string
+	if c.Requirement != nil && c.Requirement.Text != "" {
+		clauses = append(clauses, "You can use this card only if "+c.Requirement.Text+".")
+	}
+	if c.Requirement != nil && c.Requirement.DiscardFromHand > 0 {
+		clauses = append(clauses, fmt.Sprintf("Discard %s from your hand.", count(c.Requirement.DiscardFromHand, "card")))
+	}
+	if c.DependsOn == nil {
+		if flip := coinFlipSentence(c); flip != "" {
+			clauses = append(clauses, flip)
+		}
+	}
+
+	var conditions []string
+	conditions = append(conditions, requirementPhrases(c)...)
+	conditions = append(conditions, triggerPhrases(c)...)
+	conditions = append(conditions, coinResultPhrase(c)...)
+	conditions = append(conditions, durationPhrase(effect, c)...)
+
+	body := renderBody(effect, c)
+	if len(conditions) > 0 && strings.HasSuffix(conditions[len(conditions)-1], "you may") {
+		switch {
+		case effect.Type == core.EffectApplyStatus || effect.Type == core.EffectTriggeredAbility:
+			body = "make " + strings.Replace(body, " is now ", " ", 1)
+		case strings.HasPrefix(body, "this attack does "):
+			body = "do " + strings.TrimPrefix(body, "this attack does ")
+		}
+	}
+	if scaling := scalingPhrase(c.Scaling); scaling != "" && !singleFlip(c) && !strings.Contains(body, "for each") &&
+		effect.Type != core.EffectShuffleHandAndDraw {
+		body += " " + scaling
+	}
+	// "you may" runs straight on into what the player may do.
+	var sentence string
+	for _, condition := range conditions {
+		sentence += condition
+		if strings.HasSuffix(condition, "you may") {
+			sentence += " "
+		} else {
+			sentence += ", "
+		}
+	}
+	clauses = append(clauses, capitalize(sentence+body)+".")
+	return strings.Join(clauses, " ")
+}
+
+// RenderAll renders each effect and joins the results, leaving out UNKNOWN
+// effects.
+func RenderAll(parsed []core.Effect) string {
+	var sentences []string
+	for _, effect := range parsed {
+		if text := Render(effect); text != "" {
+			sentences = append(sentences, text)
+		}
+	}
+	return strings.Join(sentences, " ")
+}
+
+// renderers write the main clause of each type of effect.
+var renderers = map[core.EffectType]func(e core.Effect, c *core.Conditions) string{
+	core.EffectHeal: func(e core.Effect, c *core.Conditions) string {
+		if modifier(c).All {
+			return "heal all damage from " + pokemon(e, c, "1 of your")
+		}
+		return fmt.Sprintf("heal %d damage from %s", e.Amount, pokemon(e, c, "1 of your"))
+	},
+	core.EffectDraw: func(e core.Effect, c *core.Conditions) string {
+		return "draw " + count(e.Amount, "card")
+	},
+	core.EffectDamage: func(e core.Effect, c *core.Conditions) string {
+		if c.Scaling != nil && c.Scaling.Equals {
+			return fmt.Sprintf("this attack does damage to %s equal to %s", target(e.Target, core.TargetOpponentActive), scalingNoun(c.Scaling))
+		}
+		return fmt.Sprintf("do %d damage to %s", e.Amount, target(e.Target, core.TargetOpponentActive))
+	},
+	core.EffectCopyAttack: func(e core.Effect, c *core.Conditions) string {
+		whose := "your opponent's Active Pokémon's"
+		if filter(c).Pool == "ANY_OPPONENT" {
+			whose = "your opponent's Pokémon's"
+		}
+		text := "choose 1 of " + whose + " attacks and use it as this attack"
+		if c.Requirement != nil && c.Requirement.AttackEnergy {
+			text += ". If this Pokémon doesn't have the necessary Energy to use that attack, this attack does nothing"
+		}
+		return text
+	},
+	core.EffectApplyStatus:      applyStatus,
+	core.EffectTriggeredAbility: applyStatus,
+	core.EffectRestrictionCantAttack: func(e core.Effect, c *core.Conditions) string {
+		return target(e.Target, core.TargetOpponentActive) + " can't attack"
+	},
+	core.EffectForceSwitch: func(e core.Effect, c *core.Conditions) string {
+		if modifier(c).PlayerChooses || filter(c).Pool == "BENCHED" || c.CoinFlip != nil {
+			return "switch in 1 of your opponent's Benched " + pokemonNoun(c.Filter) + " to the Active Spot"
+		}
+		return "switch out your opponent's Active Pokémon to the Bench"
+	},
+	core.EffectSearchDeck: func(e core.Effect, c *core.Conditions) string {
+		if c.Destination != nil && c.Destination.Zone == core.ZoneBench {
+			return fmt.Sprintf("put %s from your deck onto your Bench", cardsPhrase(e.Amount, c))
+		}
+		return fmt.Sprintf("put %s from your deck into your hand", cardsPhrase(e.Amount, c))
+	},
+	core.EffectRecoilDamage: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("this Pokémon also does %d damage to itself", e.Amount)
+	},
+	core.EffectConditionalDamage: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("this attack does %d more damage", e.Amount)
+	},
+	core.EffectAttachEnergy: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("take %s from %s and attach it to %s", energyPhrase(e.Amount, c.Energy), sourcePhrase(c.Source, "your Energy Zone"), pokemon(e, c, "1 of your"))
+	},
+	core.EffectScalingDamage: func(e core.Effect, c *core.Conditions) string {
+		if e.Amount == 0 {
+			return "this attack does more damage equal to " + scalingNoun(scaling(c))
+		}
+		more := " more"
+		if c.Scaling != nil && c.Scaling.Base {
+			more = ""
+		}
+		return fmt.Sprintf("this attack does %d%s damage %s", e.Amount, more, scalingPhrase(c.Scaling))
+	},
+	core.EffectAttackMayFail: func(e core.Effect, c *core.Conditions) string { return "this attack does nothing" },
+	core.EffectDiscardEnergy: func(e core.Effect, c *core.Conditions) string {
+		amount := e.Amount
+		if modifier(c).All {
+			return fmt.Sprintf("discard all %s from %s", energyNoun(c.Energy), pokemon(e, c, "1 of your"))
+		}
+		return fmt.Sprintf("discard %s from %s", energyPhrase(amount, energyWithRandom(c)), pokemon(e, c, "1 of your"))
+	},
+	core.EffectMoveEnergy: func(e core.Effect, c *core.Conditions) string {
+		amount := e.Amount
+		if amount == 0 {
+			amount = modifier(c).Amount
+		}
+		energy := energyPhrase(amount, c.Energy)
+		if modifier(c).All {
+			energy = "all " + energyNoun(c.Energy)
+		}
+		to := destinationPhrase(c.Destination, pokemon(e, c, "1 of your"))
+		return fmt.Sprintf("move %s from %s to %s", energy, sourcePhrase(c.Source, "1 of your Benched Pokémon"), to)
+	},
+	core.EffectReduceIncomingDamage: func(e core.Effect, c *core.Conditions) string {
+		switch e.Target {
+		case core.TargetOpponentActive:
+			return fmt.Sprintf("attacks used by your opponent's Active Pokémon do −%d damage", e.Amount)
+		case core.TargetAllFriendly:
+			return fmt.Sprintf("each of your %s takes −%d damage from %s", pokemonNoun(c.Filter), e.Amount, attacksFrom(c))
+		}
+		return fmt.Sprintf("this Pokémon takes −%d damage from %s", e.Amount, attacksFrom(c))
+	},
+	core.EffectDiscardFromHand: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("discard %s from %s", cardsPhrase(e.Amount, c), target(e.Target, core.TargetOpponentHand))
+	},
+	core.EffectPassiveAbility: func(e core.Effect, c *core.Conditions) string {
+		subject := "this Pokémon"
+		if c.Filter != nil {
+			subject = "your " + pokemonNoun(c.Filter)
+			if c.Filter.Pool == "ALL_FRIENDLY" {
+				subject = "each of your " + pokemonNoun(c.Filter)
+			}
+		}
+		return passive(e, c, subject)
+	},
+	core.EffectPassiveDamage: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("do %d damage to %s", e.Amount, target(e.Target, core.TargetOpponentActive))
+	},
+	core.EffectApplyRestriction: func(e core.Effect, c *core.Conditions) string {
+		subject := target(e.Target, core.TargetOpponentActive)
+		switch restriction := modifier(c).Restriction; restriction {
+		case "CANT_ATTACK":
+			return subject + " can't attack"
+		case "CANT_RETREAT":
+			return subject + " can't retreat"
+		case "CANT_USE_ATTACK":
+			return subject + " can't use " + filter(c).AttackName
+		case "CANT_ATTACH_ENERGY":
+			return "your opponent can't attach Energy from their Energy Zone to " + subject
+		case "CANT_PLAY_CARD_TYPE":
+			return "your opponent can't play any " + filter(c).CardType + " cards from their hand"
+		case "ATTACK_MAY_FAIL":
+			return "if " + subject + " tries to use an attack, your opponent flips a coin. If tails, that attack doesn't happen"
+		case "INCREASE_ATTACK_COST":
+			return fmt.Sprintf("attacks used by %s cost %d %s more", subject, costAmount(e, c), costSymbol(c))
+		case "INCREASE_RETREAT_COST":
+			return fmt.Sprintf("%s's Retreat Cost is %d %s more", subject, costAmount(e, c), costSymbol(c))
+		default:
+			return subject + ": " + words(restriction)
+		}
+	},
+	core.EffectMultiHitRandomDamage: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("1 of your opponent's Pokémon is chosen at random %d times. For each time a Pokémon was chosen, do %d damage to it", modifier(c).Hits, e.Amount)
+	},
+	core.EffectDamageBenchedFriendly: func(e core.Effect, c *core.Conditions) string {
+		which := "1 of your Benched Pokémon"
+		if filter(c).All {
+			which = "each of your Benched Pokémon"
+		}
+		return fmt.Sprintf("this attack also does %d damage to %s", e.Amount, which)
+	},
+	core.EffectSnipeDamage: func(e core.Effect, c *core.Conditions) string {
+		if modifier(c).Random {
+			return fmt.Sprintf("1 of your opponent's %s is chosen at random. Do %d damage to it", pokemonNoun(c.Filter), e.Amount)
+		}
+		whom := target(e.Target, core.TargetOpponentActive)
+		if filter(c).Pool == "ANY_OPPONENT" {
+			whom = "1 of your opponent's " + pokemonNoun(c.Filter)
+		}
+		return fmt.Sprintf("this attack does %d damage to %s", e.Amount, whom)
+	},
+	core.EffectSwitchSelf: func(e core.Effect, c *core.Conditions) string {
+		if c.Requirement != nil && c.Requirement.Location == core.ZoneBench {
+			return "switch this Pokémon with your Active Pokémon"
+		}
+		if c.Source != nil && c.Source.Subtype != "" {
+			return fmt.Sprintf("switch your Active %s with 1 of your Benched %s", c.Source.Subtype, pokemonNoun(c.Filter))
+		}
+		return "switch this Pokémon with 1 of your Benched " + pokemonNoun(c.Filter)
+	},
+	core.EffectShuffleIntoDeck: func(e core.Effect, c *core.Conditions) string {
+		return "your opponent shuffles their Active Pokémon into their deck"
+	},
+	core.EffectApplyPrevention: func(e core.Effect, c *core.Conditions) string {
+		switch modifier(c).Prevent {
+		case "ALL_DAMAGE_AND_EFFECTS":
+			return "prevent all damage from—and effects of—attacks done to " + target(e.Target, core.TargetSelf)
+		case "ALL_DAMAGE", "":
+			return "prevent all damage done to " + target(e.Target, core.TargetSelf) + " by attacks"
+		default:
+			return "prevent " + words(modifier(c).Prevent) + " done to " + target(e.Target, core.TargetSelf)
+		}
+	},
+	core.EffectScalingSnipeDamage: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("this attack does %d damage to 1 of your opponent's Pokémon %s", e.Amount, scalingPhrase(c.Scaling))
+	},
+	core.EffectDamageBenchedOpponentAll: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("this attack also does %d damage to each of your opponent's Benched Pokémon", e.Amount)
+	},
+	core.EffectLifesteal: func(e core.Effect, c *core.Conditions) string {
+		return "heal from this Pokémon the same amount of damage you did to your opponent's Active Pokémon"
+	},
+	core.EffectApplyReactiveDamage: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("if this Pokémon is damaged by an attack, do %d damage to the Attacking Pokémon", e.Amount)
+	},
+	core.EffectBuffNextTurn: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("during your next turn, this Pokémon's %s attack does +%d damage", filter(c).AttackName, e.Amount)
+	},
+	core.EffectModifyEnergy: func(e core.Effect, c *core.Conditions) string {
+		what := "a random Energy attached to " + target(e.Target, core.TargetOpponentActive)
+		if c.Energy != nil && c.Energy.Next {
+			what = "the next Energy that will be generated for your opponent"
+		}
+		var types []core.EnergyType
+		if c.Energy != nil {
+			types = c.Energy.PossibleTypes
+		}
+		return "change the type of " + what + " to 1 of the following at random: " + energyList(types, "or")
+	},
+	core.EffectDamageAllOpponent: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("this attack does %d damage to each of your opponent's Pokémon", e.Amount)
+	},
+	core.EffectDiscardDeck: func(e core.Effect, c *core.Conditions) string {
+		deck := "your deck"
+		switch {
+		case filter(c).Pool == "BOTH_PLAYERS":
+			deck = "each player's deck"
+		case filter(c).Player == "OPPONENT":
+			deck = "your opponent's deck"
+		}
+		return fmt.Sprintf("discard the top %s of %s", count(e.Amount, "card"), deck)
+	},
+	core.EffectSetHP: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("%s's remaining HP is now %d", target(e.Target, core.TargetOpponentActive), e.Amount)
+	},
+	core.EffectShuffleFromHand: func(e core.Effect, c *core.Conditions) string {
+		if c.Scaling != nil {
+			return "a card is chosen at random from your opponent's hand. Your opponent reveals that card and shuffles it into their deck"
+		}
+		return fmt.Sprintf("your opponent reveals %s from their hand and shuffles it into their deck", cardsPhrase(e.Amount, c))
+	},
+	core.EffectLookAtDeck: func(e core.Effect, c *core.Conditions) string {
+		deck := "your deck"
+		switch filter(c).Player {
+		case "OPPONENT":
+			deck = "your opponent's deck"
+		case "EITHER":
+			deck = "either player's deck"
+		}
+		if modifier(c).Reveal {
+			return fmt.Sprintf("your opponent reveals all of the %s cards in their deck", filter(c).CardType)
+		}
+		top := "card"
+		if e.Amount > 1 {
+			top = fmt.Sprintf("%d cards", e.Amount)
+		}
+		text := fmt.Sprintf("look at the top %s of %s", top, deck)
+		if c.Destination != nil && c.Destination.OnMatch != "" {
+			text += fmt.Sprintf(". Put any %s you find there into your %s, and %s", pokemonOrCards(c), words(c.Destination.OnMatch), words(c.Destination.Otherwise))
+		}
+		if modifier(c).MayShuffle {
+			text += ". You may shuffle your deck"
+		}
+		return text
+	},
+	core.EffectDelayedDamage: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("do %d damage to %s", e.Amount, target(e.Target, core.TargetOpponentActive))
+	},
+	core.EffectKnockout: func(e core.Effect, c *core.Conditions) string {
+		return target(e.Target, core.TargetOpponentActive) + " is Knocked Out"
+	},
+	core.EffectMoveDamage: func(e core.Effect, c *core.Conditions) string {
+		amount := fmt.Sprintf("%d", e.Amount)
+		if modifier(c).All {
+			amount = "all"
+		}
+		from := "1 of your " + pokemonNoun(c.Filter)
+		if c.Source != nil && c.Source.Pool == "ANY_FRIENDLY_DAMAGED" {
+			from = "1 of your Pokémon that has damage on it"
+		}
+		return fmt.Sprintf("move %s of the damage from %s to %s", amount, from, destinationPhrase(c.Destination, target(e.Target, core.TargetOpponentActive)))
+	},
+	core.EffectDiscardTool: func(e core.Effect, c *core.Conditions) string {
+		if filter(c).All {
+			return "discard all Pokémon Tools from each of your opponent's Pokémon"
+		}
+		return "discard all Pokémon Tools from " + target(e.Target, core.TargetOpponentActive)
+	},
+	core.EffectRevealHand: func(e core.Effect, c *core.Conditions) string {
+		return "your opponent reveals their hand"
+	},
+	core.EffectDamageHalveHP: func(e core.Effect, c *core.Conditions) string {
+		return "halve " + target(e.Target, core.TargetOpponentActive) + "'s remaining HP, rounded down"
+	},
+	core.EffectReturnToHand: func(e core.Effect, c *core.Conditions) string {
+		if e.Target == core.TargetOpponentActive {
+			return "put your opponent's Active Pokémon into their hand"
+		}
+		return "put " + pokemon(e, c, "1 of your") + " into your hand"
+	},
+	core.EffectDiscardBenched: func(e core.Effect, c *core.Conditions) string {
+		return "discard any number of your Benched " + pokemonNoun(c.Filter)
+	},
+	core.EffectDevolve: func(e core.Effect, c *core.Conditions) string {
+		return "devolve " + target(e.Target, core.TargetOpponentActive) + " by putting the highest Stage Evolution card on it into your opponent's hand"
+	},
+	core.EffectDebuffIncomingDamage: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("%s takes +%d damage from attacks", target(e.Target, core.TargetSelf), e.Amount)
+	},
+
+	core.EffectPlayAsBasic: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("play this card as if it were a %d-HP Basic %s Pokémon. At any time during your turn, you may discard this card from play. This card can't retreat", e.Amount, symbol(energy(c).Type))
+	},
+	core.EffectToolAttachment: func(e core.Effect, c *core.Conditions) string {
+		return passive(e, c, "the "+pokemonNoun(c.Filter)+" this card is attached to")
+	},
+	core.EffectShuffleHandAndDraw: func(e core.Effect, c *core.Conditions) string {
+		switch {
+		case filter(c).Player == "BOTH":
+			return "each player shuffles the cards in their hand into their deck, then draws that many cards"
+		case c.Scaling != nil && c.Scaling.By == "REMAINING_POINTS":
+			return "your opponent shuffles their hand into their deck and draws a card for each of their remaining points needed to win"
+		}
+		return "your opponent shuffles their hand into their deck and draws " + count(e.Amount, "card")
+	},
+	core.EffectBuffDamage: func(e core.Effect, c *core.Conditions) string {
+		opponent := "your opponent's Active Pokémon"
+		if c.Opponent != nil && c.Opponent.Subtype != "" {
+			opponent = "your opponent's Active Pokémon " + c.Opponent.Subtype
+		}
+		return fmt.Sprintf("attacks used by your %s do +%d damage to %s", pokemonNoun(c.Filter), e.Amount, opponent)
+	},
+	core.EffectReduceRetreatCost: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("the Retreat Cost of %s is %d less", target(e.Target, core.TargetActiveFriendly), e.Amount)
+	},
+	core.EffectReduceAttackCost: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("attacks used by your %s cost %d less %s", pokemonNoun(c.Filter), e.Amount, energyNoun(c.Energy))
+	},
+	core.EffectRecoverStatus: func(e core.Effect, c *core.Conditions) string {
+		if modifier(c).Random {
+			return "remove a random Special Condition from " + target(e.Target, core.TargetActiveFriendly)
+		}
+		return pokemon(e, c, "1 of your") + " recovers from " + statusesPhrase(modifier(c))
+	},
+	core.EffectRecoverFromDiscard: func(e core.Effect, c *core.Conditions) string {
+		if c.Destination != nil && c.Destination.Zone == core.ZoneBench {
+			return fmt.Sprintf("put %s from your opponent's discard pile onto their Bench", cardsPhrase(e.Amount, c))
+		}
+		if c.Scaling != nil {
+			return fmt.Sprintf("a %s is chosen at random from your discard pile and put into your hand", pokemonNoun(c.Filter))
+		}
+		return fmt.Sprintf("put %s from your discard pile into your hand", cardsPhrase(e.Amount, c))
+	},
+	core.EffectEvolveSkippingStage: func(e core.Effect, c *core.Conditions) string {
+		f := filter(c)
+		return fmt.Sprintf("choose 1 of your %s Pokémon in play. If you have a %s card in your hand that evolves from that Pokémon, put that card onto the %s Pokémon to evolve it, skipping the Stage 1. You can't use this card during your first turn or on a %s Pokémon that was put into play this turn", f.Stage, f.EvolutionStage, f.Stage, f.Stage)
+	},
+	core.EffectSwapWithDeck: func(e core.Effect, c *core.Conditions) string {
+		return "choose a Pokémon in your hand and switch it with a random Pokémon in your deck"
+	},
+	core.EffectRearrangeDeck: func(e core.Effect, c *core.Conditions) string {
+		deck := "your deck"
+		if filter(c).Player == "OPPONENT" {
+			deck = "your opponent's deck"
+		}
+		return fmt.Sprintf("for each of your %s Pokémon in play, look at that many cards from the top of %s and put them back in any order", symbol(scaling(c).Type), deck)
+	},
+	core.EffectCopySupporter: func(e core.Effect, c *core.Conditions) string {
+		return fmt.Sprintf("look at a random Supporter card that's not %s from your opponent's deck and shuffle it back into their deck. Use the effect of that card as the effect of this card", filter(c).ExcludeName)
+	},
+	core.EffectGuaranteeHeads: func(e core.Effect, c *core.Conditions) string {
+		return "the next time you flip any number of coins for the effect of an attack, Ability, or Trainer card after using this card on this turn, the first coin flip will definitely be heads"
+	},
+}
+
+func renderBody(e core.Effect, c *core.Conditions) string {
+	if render, ok := renderers[e.Type]; ok {
+		return render(e, c)
+	}
+	text := words(string(e.Type))
+	if e.Amount != 0 {
+		text += fmt.Sprintf(" %d", e.Amount)
+	}
+	return text
+}
+
+func applyStatus(e core.Effect, c *core.Conditions) string {
+	subject := target(e.Target, core.TargetOpponentActive)
+	if m := modifier(c); len(m.PossibleStatuses) > 0 {
+		return fmt.Sprintf("1 Special Condition from among %s is chosen at random, and %s is now affected by that Special Condition", statusList(m.PossibleStatuses, "and"), subject)
+	}
+	statuses := []core.StatusCondition{e.Status}
+	if e.Status == "" {
+		statuses = modifier(c).Statuses
+	}
+	return subject + " is now " + statusList(statuses, "and")
+}
+
+// passive writes the lasting effect of a passive ability or Pokémon Tool
+// on subject.
+func passive(e core.Effect, c *core.Conditions, subject string) string {
+	m := modifier(c)
+	switch m.Effect {
+	case "REDUCE_INCOMING_DAMAGE":
+		return fmt.Sprintf("%s takes −%d damage from %s", subject, amountOf(e, m), attacksFrom(c))
+	case "ZERO_RETREAT_COST":
+		return subject + " has no Retreat Cost"
+	case "REDUCE_RETREAT_COST":
+		return fmt.Sprintf("%s has a Retreat Cost that is %d less", subject, amountOf(e, m))
+	case "BUFF_DAMAGE_OUTPUT", "BUFF_DAMAGE":
+		return fmt.Sprintf("attacks used by %s do +%d damage to your opponent's Active Pokémon", subject, amountOf(e, m))
+	case "BUFF_HP":
+		return fmt.Sprintf("%s gets +%d HP", subject, amountOf(e, m))
+	case "IMMUNE_TO_SPECIAL_CONDITIONS", "IMMUNE_TO_STATUS", "STATUS_IMMUNITY":
+		if c.Trigger != nil && c.Trigger.Status != "" {
+			return subject + " can't be " + statusName(c.Trigger.Status)
+		}
+		return subject + " can't be affected by any Special Conditions"
+	case "BUFF_STATUS_DAMAGE":
+		status := core.StatusPoisoned
+		if c.Trigger != nil && c.Trigger.Status != "" {
+			status = c.Trigger.Status
+		}
+		return fmt.Sprintf("your opponent's Active Pokémon takes +%d damage from being %s", amountOf(e, m), statusName(status))
+	case "RESTRICT_OPPONENT_PLAY":
+		return "your opponent can't use any " + filter(c).CardType + " cards from their hand"
+	case "RESTRICT_OPPONENT_EVOLVE":
+		return "your opponent can't play any Pokémon from their hand to evolve their Active Pokémon"
+	case "ENERGY_VALUE_DOUBLED":
+		return fmt.Sprintf("each %s attached to %s provides 2 %s", energyNoun(c.Energy), subject, energyNoun(c.Energy))
+	case "PREVENT_INCOMING_EFFECTS":
+		return "prevent all effects of attacks used by your opponent's Pokémon done to " + subject
+	case "PREVENT_HEALING":
+		return "Pokémon (both yours and your opponent's) can't be healed"
+	case "PREVENT_KNOCKOUT":
+		return fmt.Sprintf("if %s would be Knocked Out by damage from an attack, it is not Knocked Out, and its remaining HP becomes %d", subject, m.RemainingHP)
+	case "CAN_EVOLVE_INTO_ANY":
+		if from := filter(c).EvolvesFrom; from != "" {
+			return subject + " can evolve into any Pokémon that evolves from " + from
+		}
+		return subject + " can evolve into any Pokémon"
+	case "EVOLVE":
+		return "put a random card from your deck that evolves from " + subject + " onto it to evolve it"
+	case "ALTERNATE_ATTACK_COST":
+		return fmt.Sprintf("this attack can be used for %d %s Energy", m.CostAmount, symbol(m.CostType))
+	case "USE_PREVIOUS_EVOLUTION_ATTACKS":
+		return subject + " can use any attack from its previous Evolutions"
+	case "REACTIVE_DAMAGE_ON_KO":
+		return fmt.Sprintf("if %s is Knocked Out by damage from an attack from your opponent's Pokémon, do %d damage to the Attacking Pokémon", subject, amountOf(e, m))
+	case "KO_ATTACKER_ON_KO":
+		return "if " + subject + " is Knocked Out by damage from an attack from your opponent's Pokémon, the Attacking Pokémon is Knocked Out"
+	case "MOVE_ENERGY_ON_KO", "MOVE_ENERGY_ON_KNOCKOUT":
+		return fmt.Sprintf("if %s is Knocked Out by damage from an attack from your opponent's Pokémon, move %s from it to %s", subject, energyPhrase(amountOf(e, m), c.Energy), destinationPhrase(c.Destination, "1 of your Benched Pokémon"))
+	case "APPLY_PREVENTION_ON_KO":
+		return "if your opponent's Pokémon is Knocked Out by damage from " + subject + "'s attacks, prevent all damage from—and effects of—attacks done to " + subject
+	case "RETURN_TO_HAND_ON_KNOCKOUT":
+		return "if " + subject + " is Knocked Out by damage from an attack from your opponent's Pokémon, put it into your hand instead of the discard pile"
+	case "REACTIVE_SHUFFLE_FROM_HAND":
+		return "if " + subject + " is damaged by an attack from your opponent's Pokémon, your opponent reveals a random card from their hand and shuffles it into their deck"
+	case "RECOVER_STATUS":
+		return subject + " recovers from " + statusesPhrase(m)
+	case "PREVENT_INCOMING_DAMAGE":
+		return "prevent all damage done to " + subject + " by " + attacksFrom(c)
+	case "REDUCE_ATTACK_COST":
+		return fmt.Sprintf("attacks used by %s cost %d less %s", subject, amountOf(e, m), energyNoun(c.Energy))
+	case "INCREASE_OPPONENT_ATTACK_COST":
+		return fmt.Sprintf("attacks used by your opponent's Active Pokémon cost %d %s more", amountOf(e, m), symbol(energy(c).Type))
+	case "REDUCE_OPPONENT_DAMAGE_OUTPUT":
+		return fmt.Sprintf("attacks used by your opponent's Active Pokémon do −%d damage", amountOf(e, m))
+	case "REACTIVE_DAMAGE":
+		return fmt.Sprintf("if %s is damaged by an attack from your opponent's Pokémon, do %d damage to the Attacking Pokémon", subject, amountOf(e, m))
+	case "REACTIVE_STATUS":
+		return fmt.Sprintf("if %s is damaged by an attack from your opponent's Pokémon, the Attacking Pokémon is now %s", subject, statusName(e.Status))
+	case "HEAL":
+		return fmt.Sprintf("heal %d damage from %s", amountOf(e, m), subject)
+	}
+	text := subject + ": " + words(m.Effect)
+	if amount := amountOf(e, m); amount != 0 {
+		text += fmt.Sprintf(" %d", amount)
+	}
+	return text
+}
+
+// costAmount and costSymbol give the Energy an effect adds to or takes
+// off a cost.
+func costAmount(e core.Effect, c *core.Conditions) int {
+	if m := modifier(c); m.CostAmount != 0 {
+		return m.CostAmount
+	}
+	return amountOf(e, modifier(c))
+}
+
+func costSymbol(c *core.Conditions) string {
+	if t := modifier(c).CostType; t != "" {
+		return symbol(t)
+	}
+	if t := energy(c).Type; t != "" {
+		return symbol(t)
+	}
+	return symbol(core.EnergyColorless)
+}
+
+// attacksFrom writes the attacks a defensive effect applies to.
+func attacksFrom(c *core.Conditions) string {
+	if c.Attacker == nil {
+		return "attacks"
+	}
+	if len(c.Attacker.Types) > 0 {
+		return "attacks from " + energyList(c.Attacker.Types, "or") + " Pokémon"
+	}
+	if c.Attacker.Subtype != "" {
+		return "attacks from your opponent's Pokémon " + c.Attacker.Subtype
+	}
+	return "attacks"
+}
+
+func amountOf(e core.Effect, m *core.Modifier) int {
+	if e.Amount != 0 {
+		return e.Amount
+	}
+	return m.Amount
+}
+
+// singleFlip reports whether an effect's amount is scaled by the heads of
+// a single coin, which card text writes as "Flip a coin. If heads, ...".
+func singleFlip(c *core.Conditions) bool {
+	if c.Scaling == nil || c.Scaling.By != "COIN_FLIP_HEADS" {
+		return false
+	}
+	return c.CoinFlip == nil || c.CoinFlip.Flips <= 1 && c.CoinFlip.FlipsPer == ""
+}
+
+// coinFlipSentence announces the coins an effect flips.
+func coinFlipSentence(c *core.Conditions) string {
+	if c.CoinFlip == nil {
+		switch {
+		case c.Scaling != nil && c.Scaling.By == "COIN_FLIP_HEADS_UNTIL_TAILS":
+			return "Flip a coin until you get tails."
+		case c.Scaling != nil && c.Scaling.By == "COIN_FLIP_HEADS":
+			return "Flip a coin."
+		}
+		return ""
+	}
+	flip := c.CoinFlip
+	switch {
+	case flip.FlipsPer != "":
+		return "Flip a coin for each " + flipsPerNoun(flip.FlipsPer, c) + "."
+	case flip.Flips > 1:
+		return fmt.Sprintf("Flip %d coins.", flip.Flips)
+	case flip.Result == core.CoinDoubleHeads:
+		return "Flip 2 coins."
+	case flip.Result != "" || c.Scaling != nil && strings.HasPrefix(c.Scaling.By, "COIN_FLIP"):
+		return "Flip a coin."
+	}
+	return ""
+}
+
+func coinResultPhrase(c *core.Conditions) []string {
+	if singleFlip(c) {
+		return []string{"if heads"}
+	}
+	if c.CoinFlip == nil {
+		return nil
+	}
+	switch c.CoinFlip.Result {
+	case core.CoinHeads:
+		if c.CoinFlip.Heads == 1 {
+			return []string{"if at least 1 of them is heads"}
+		}
+		if c.CoinFlip.Heads > 1 {
+			return []string{fmt.Sprintf("if at least %d of them are heads", c.CoinFlip.Heads)}
+		}
+		return []string{"if heads"}
+	case core.CoinTails:
+		return []string{"if tails"}
+	case core.CoinDoubleHeads:
+		return []string{"if both of them are heads"}
+	}
+	return nil
+}
+
+func durationPhrase(e core.Effect, c *core.Conditions) []string {
+	if e.Type == core.EffectBuffNextTurn {
+		return nil // The body says when
+	}
+	switch c.Duration {
+	case core.DurationThisTurn:
+		return []string{"during this turn"}
+	case core.DurationNextTurn:
+		return []string{"during your next turn"}
+	case core.DurationOpponentNextTurn:
+		return []string{"during your opponent's next turn"}
+	case core.DurationFirstTurn:
+		return []string{"during your first turn"}
+	case core.DurationPersistentActive:
+		return []string{"as long as this Pokémon is in the Active Spot"}
+	}
+	return nil
+}
+
+// triggerPhrases writes what sets an effect off, or what must be true for it
+// to happen.
+func triggerPhrases(c *core.Conditions) []string {
+	t := c.Trigger
+	if t == nil {
+		return nil
+	}
+	var phrases []string
+	if t.Phase == "CHECKUP" {
+		phrases = append(phrases, "during Pokémon Checkup")
+	} else if t.Phase != "" {
+		phrases = append(phrases, "during "+words(t.Phase))
+	}
+	switch t.Event {
+	case "":
+	case core.TriggerOncePerTurn:
+		phrases = append(phrases, "once during your turn, you may")
+	case core.TriggerAsOftenAsYouLike:
+		phrases = append(phrases, "as often as you like during your turn, you may")
+	case core.TriggerEndOfTurn:
+		phrases = append(phrases, "at the end of your turn")
+	case core.TriggerEndOfFirstTurn:
+		phrases = append(phrases, "at the end of your first turn")
+	case core.TriggerEndOfOpponentNextTurn:
+		phrases = append(phrases, "at the end of your opponent's next turn")
+	case core.TriggerKnockedOut:
+		phrases = append(phrases, "if this Pokémon is Knocked Out by damage from an attack from your opponent's Pokémon")
+	case core.TriggerOnEvolve:
+		phrases = append(phrases, "when you play this Pokémon from your hand to evolve 1 of your Pokémon during your turn, you may")
+	case core.TriggerOnPlayToBench:
+		phrases = append(phrases, "when you put this Pokémon from your hand onto your Bench, you may")
+	case core.TriggerAttachEnergyToSelf:
+		phrases = append(phrases, "whenever you attach "+energyNoun(c.Energy)+" from your Energy Zone to this Pokémon")
+	case core.TriggerAttackUsedLastTurn:
+		phrases = append(phrases, "if this Pokémon used "+t.AttackName+" during your last turn")
+	case core.TriggerDamagedLastTurn:
+		phrases = append(phrases, "if this Pokémon was damaged by an attack during your opponent's last turn")
+	case core.TriggerFriendlyKOLastTurn:
+		phrases = append(phrases, "if any of your Pokémon were Knocked Out by damage from an attack during your opponent's last turn")
+	case core.TriggerEvolvedThisTurn:
+		phrases = append(phrases, "if this Pokémon evolved during this turn")
+	case core.TriggerSwitchedInThisTurn:
+		phrases = append(phrases, "if this Pokémon moved from your Bench to the Active Spot this turn")
+	case core.TriggerPlayedSupporterThisTurn:
+		phrases = append(phrases, "if you played a Supporter card from your hand during this turn")
+	case core.TriggerDiscardedCardIsType:
+		phrases = append(phrases, "discard the top card of your deck. If that card is a "+symbol(t.Type)+" Pokémon")
+	case core.TriggerDifferentEnergyTypesAttached:
+		phrases = append(phrases, fmt.Sprintf("if this Pokémon has at least %d different types of Energy attached", t.Count))
+	case core.TriggerHasEnergyAttached, core.TriggerSelfHasTypedEnergy:
+		phrases = append(phrases, "if this Pokémon has "+energyNoun(c.Energy)+" attached")
+	case core.TriggerSelfHasDamage:
+		phrases = append(phrases, "if this Pokémon has damage on it")
+	case core.TriggerSelfHasNoDamage:
+		phrases = append(phrases, "if this Pokémon has no damage on it")
+	case core.TriggerSelfHasTool:
+		phrases = append(phrases, "if this Pokémon has a Pokémon Tool attached")
+	case core.TriggerAnyBenchedFriendlyHasDamage:
+		phrases = append(phrases, "if any of your Benched Pokémon have damage on them")
+	case core.TriggerPokemonOnBench:
+		phrases = append(phrases, "if "+t.Name+" is on your Bench")
+	case core.TriggerOpponentHasAbility:
+		phrases = append(phrases, "if your opponent's Active Pokémon has an Ability")
+	case core.TriggerOpponentHasDamage:
+		phrases = append(phrases, "if your opponent's Active Pokémon has damage on it")
+	case core.TriggerOpponentHasProperty:
+		phrases = append(phrases, "if your opponent's Active Pokémon is "+property(t.Property))
+	case core.TriggerOpponentHasSpecialCondition:
+		phrases = append(phrases, "if your opponent's Active Pokémon is affected by a Special Condition")
+	case core.TriggerOpponentHasStatus:
+		phrases = append(phrases, "if your opponent's Active Pokémon is "+statusName(t.Status))
+	case core.TriggerOpponentHasTool:
+		phrases = append(phrases, "if your opponent's Active Pokémon has a Pokémon Tool attached")
+	case core.TriggerOpponentHPGreater:
+		phrases = append(phrases, "if your opponent's Active Pokémon has more remaining HP than this Pokémon")
+	case core.TriggerOpponentIsEvolved:
+		phrases = append(phrases, "if your opponent's Active Pokémon is an evolved Pokémon")
+	case core.TriggerOpponentIsEX:
+		phrases = append(phrases, "if your opponent's Active Pokémon is a Pokémon ex")
+	case core.TriggerOpponentIsName:
+		phrases = append(phrases, "if your opponent's Active Pokémon is "+t.Name)
+	case core.TriggerOpponentIsStage:
+		phrases = append(phrases, "if your opponent's Active Pokémon is a "+t.Stage+" Pokémon")
+	case core.TriggerOpponentKO:
+		phrases = append(phrases, "if your opponent's Pokémon is Knocked Out by damage from this attack")
+	default:
+		phrases = append(phrases, "when "+words(string(t.Event)))
+	}
+	return phrases
+}
+
+func requirementPhrases(c *core.Conditions) []string {
+	r := c.Requirement
+	if r == nil {
+		return nil
+	}
+	var phrases []string
+	switch r.Location {
+	case core.ZoneActive:
+		phrases = append(phrases, "if this Pokémon is in the Active Spot")
+	case core.ZoneBench:
+		phrases = append(phrases, "if this Pokémon is on your Bench")
+	}
+	if len(r.InPlay) > 0 {
+		phrases = append(phrases, "if you have "+list(r.InPlay, "or")+" in play")
+	}
+	if r.ExtraEnergy > 0 {
+		phrases = append(phrases, fmt.Sprintf("if this Pokémon has at least %d extra %s Energy attached", r.ExtraEnergy, symbol(r.EnergyType)))
+	}
+	return phrases
+}
+
+// scalingPhrase writes what an amount is multiplied by, as "for each ...".
+func scalingPhrase(s *core.Scaling) string {
+	if s == nil || s.Equals {
+		return ""
+	}
+	return "for each " + scalingNoun(s)
+}
+
+func scalingNoun(s *core.Scaling) string {
+	var noun string
+	switch s.By {
+	case "COIN_FLIP_HEADS":
+		noun = "heads"
+	case "COIN_FLIP_HEADS_UNTIL_TAILS":
+		noun = "heads"
+	case "BENCHED_POKEMON_COUNT":
+		noun = "of your Benched Pokémon"
+	case "ALL_BENCHED_POKEMON_COUNT":
+		noun = "Benched Pokémon (both yours and your opponent's)"
+	case "OPPONENT_BENCHED_POKEMON_COUNT":
+		noun = "of your opponent's Benched Pokémon"
+	case "BENCHED_POKEMON_TYPE", "BENCHED_POKEMON_TYPE_COUNT":
+		kind := symbol(s.Type) + " Pokémon"
+		if s.Evolved {
+			kind = "Evolution Pokémon"
+		}
+		noun = strings.TrimSpace(kind) + " on your Bench"
+	case "BENCHED_POKEMON_NAME", "BENCHED_POKEMON_NAMES":
+		noun = "of your Benched " + list(s.Names, "and")
+	case "SELF_ATTACHED_ENERGY":
+		noun = strings.TrimSpace(symbol(s.Type)+" Energy") + " attached to this Pokémon"
+	case "OPPONENT_ATTACHED_ENERGY":
+		noun = "Energy attached to your opponent's Active Pokémon"
+	case "TARGET_ATTACHED_ENERGY":
+		noun = "Energy attached to that Pokémon"
+	case "ALL_OPPONENT_POKEMON_ENERGY":
+		noun = "Energy attached to all of your opponent's Pokémon"
+	case "SELF_DAMAGE_COUNTERS":
+		noun = "the damage this Pokémon has on it"
+	case "OPPONENT_HAND_SIZE":
+		noun = "card in your opponent's hand"
+	case "OPPONENT_RETREAT_COST":
+		noun = "{C} in your opponent's Active Pokémon's Retreat Cost"
+	case "DISCARD_TOOL_FROM_HAND":
+		noun = "Pokémon Tool card you discarded from your hand in this way"
+	case "DISCARDED_BENCHED_COUNT":
+		noun = "Benched Pokémon you discarded in this way"
+	case "ATTACK_USAGE_COUNT":
+		noun = "time your Pokémon used this attack during this game"
+	case "POINTS":
+		noun = "point you have gotten"
+	case "HAND_SIZE":
+		noun = "card in your hand"
+	case "POKEMON_IN_PLAY":
+		noun = "Pokémon you have in play"
+	default:
+		noun = words(s.By)
+	}
+	if s.Max > 0 {
+		noun += fmt.Sprintf(", up to %d", s.Max)
+	}
+	return noun
+}
+
+// flipsPerNoun writes what a coin is flipped for each of.
+func flipsPerNoun(per string, c *core.Conditions) string {
+	switch per {
+	case "SELF_ATTACHED_ENERGY":
+		return "Energy attached to this Pokémon"
+	case "SELF_ATTACHED_ENERGY_TYPED":
+		t := scaling(c).Type
+		if t == "" {
+			t = energy(c).Type
+		}
+		return energyNoun(&core.Energy{Type: t}) + " attached to this Pokémon"
+	case "ALL_POKEMON_IN_PLAY":
+		return "Pokémon you have in play"
+	}
+	return words(per)
+}
+
+// targetPhrases name the Pokémon or place each target type refers to.
+var targetPhrases = map[core.TargetType]string{
+	core.TargetSelf:               "this Pokémon",
+	core.TargetOpponentActive:     "your opponent's Active Pokémon",
+	core.TargetOpponentHand:       "your opponent's hand",
+	core.TargetAllFriendly:        "each of your Pokémon",
+	core.TargetAllPokemonInPlay:   "each Pokémon in play",
+	core.TargetBenchedFriendly:    "1 of your Benched Pokémon",
+	core.TargetBenchedOpponent:    "1 of your opponent's Benched Pokémon",
+	core.TargetBenchedOpponentAll: "each of your opponent's Benched Pokémon",
+	core.TargetDeck:               "your deck",
+	core.TargetEnergyZone:         "your Energy Zone",
+	core.TargetActiveFriendly:     "your Active Pokémon",
+	core.TargetAttached:           "the Pokémon this card is attached to",
+}
+
+func target(t, otherwise core.TargetType) string {
+	if t == "" {
+		t = otherwise
+	}
+	if phrase, ok := targetPhrases[t]; ok {
+		return phrase
+	}
+	return words(string(t))
+}
+
+// pokemon names the Pokémon an effect applies to: its filter if it has
+// one, introduced by which, or else its target.
+func pokemon(e core.Effect, c *core.Conditions, which string) string {
+	if c.Filter != nil && (c.Filter.Names != nil || c.Filter.Type != "" || c.Filter.Stage != "" || c.Filter.Subtype != "" || c.Filter.EvolvesFrom != "" || c.Filter.Condition != "") {
+		noun := pokemonNoun(c.Filter)
+		switch e.Target {
+		case core.TargetAllFriendly:
+			which = "each of your"
+		case core.TargetBenchedFriendly:
+			noun = "Benched " + noun
+		case core.TargetActiveFriendly:
+			return "your " + noun + " in the Active Spot"
+		}
+		if c.Filter.All {
+			which = "each of your"
+		}
+		return which + " " + noun
+	}
+	return target(e.Target, core.TargetSelf)
+}
+
+// pokemonNoun writes a filter as card text names Pokémon, as in "Basic {W}
+// Pokémon that has damage on it".
+func pokemonNoun(f *core.Filter) string {
+	if f == nil {
+		return "Pokémon"
+	}
+	var noun string
+	switch {
+	case len(f.Names) > 0:
+		noun = list(f.Names, "or")
+	case f.EvolvesFrom != "":
+		noun = "Pokémon that evolve from " + f.EvolvesFrom
+	case f.Subtype != "":
+		noun = f.Subtype
+	default:
+		noun = strings.Join(nonEmpty(f.Stage, symbol(f.Type), "Pokémon"), " ")
+	}
+	switch {
+	case f.Location == core.ZoneBench || f.Pool == "BENCHED":
+		noun = "Benched " + noun
+	case f.Location == core.ZoneActive || f.Pool == "ACTIVE":
+		noun = "Active " + noun
+	}
+	if f.Condition == "DAMAGED" {
+		noun += " that has damage on it"
+	}
+	if f.Energy != "" {
+		noun += " that has any " + symbol(f.Energy) + " Energy attached"
+	}
+	return noun
+}
+
+// cardsPhrase writes amount cards picked by an effect's filter, as in "a
+// random Pokémon Tool card".
+func cardsPhrase(amount int, c *core.Conditions) string {
+	noun := pokemonOrCards(c)
+	if amount <= 1 {
+		if modifier(c).Random {
+			return "a random " + noun
+		}
+		return "1 " + noun
+	}
+	if modifier(c).Random {
+		return fmt.Sprintf("%d random %s", amount, noun)
+	}
+	return fmt.Sprintf("%d %s", amount, noun)
+}
+
+func pokemonOrCards(c *core.Conditions) string {
+	if f := filter(c); f.CardType != "" {
+		return f.CardType
+	}
+	if c.Filter != nil {
+		return pokemonNoun(c.Filter)
+	}
+	return "card"
+}
+
+func sourcePhrase(s *core.Source, otherwise string) string {
+	if s == nil {
+		return otherwise
+	}
+	var phrase string
+	switch s.Zone {
+	case core.ZoneEnergyZone:
+		phrase = "your Energy Zone"
+	case core.ZoneDiscardPile:
+		phrase = "your discard pile"
+	case core.ZoneBench:
+		phrase = "1 of your Benched " + strings.Join(nonEmpty(symbol(s.Type), "Pokémon"), " ")
+	case core.ZoneSelf:
+		phrase = "this Pokémon"
+	case core.ZoneActive:
+		phrase = "your Active Pokémon"
+	default:
+		phrase = otherwise
+	}
+	if s.Pool == "ALL_FRIENDLY" {
+		phrase = "your other Pokémon"
+	}
+	return phrase
+}
+
+func destinationPhrase(d *core.Destination, otherwise string) string {
+	if d == nil {
+		return otherwise
+	}
+	switch d.Zone {
+	case core.ZoneActive:
+		return "your Active " + strings.Join(nonEmpty(symbol(d.Type), "Pokémon"), " ")
+	case core.ZoneBench:
+		return "1 of your Benched " + strings.Join(nonEmpty(symbol(d.Type), "Pokémon"), " ")
+	case core.ZoneSelf:
+		return "this Pokémon"
+	case core.ZoneHand:
+		return "your hand"
+	}
+	return otherwise
+}
+
+// energyPhrase writes amount Energy as card text does, as in "a {R} Energy"
+// or "2 random Energy".
+func energyPhrase(amount int, e *core.Energy) string {
+	if e != nil && len(e.Types) > 0 {
+		return energyList(e.Types, "and") + " Energy"
+	}
+	if e != nil && len(e.PossibleTypes) > 0 && amount <= 1 {
+		return "a " + energyList(e.PossibleTypes, "or") + " Energy"
+	}
+	noun := energyNoun(e)
+	if e != nil && e.Random {
+		noun = "random " + noun
+	}
+	if amount <= 1 {
+		return article(noun) + " " + noun
+	}
+	return fmt.Sprintf("%d %s", amount, noun)
+}
+
+func energyNoun(e *core.Energy) string {
+	if e == nil || e.Type == "" {
+		return "Energy"
+	}
+	return symbol(e.Type) + " Energy"
+}
+
+func energyWithRandom(c *core.Conditions) *core.Energy {
+	e := energy(c)
+	if modifier(c).Random {
+		copied := *e
+		copied.Random = true
+		return &copied
+	}
+	return e
+}
+
+func energyList(types []core.EnergyType, conjunction string) string {
+	symbols := make([]string, len(types))
+	for i, t := range types {
+		symbols[i] = symbol(t)
+	}
+	return list(symbols, conjunction)
+}
+
+// symbol writes an energy type as card text does, as in "{R}".
+func symbol(t core.EnergyType) string {
+	if t == "" {
+		return ""
+	}
+	if s := t.Symbol(); s != "" {
+		return "{" + s + "}"
+	}
+	return string(t)
+}
+
+func statusesPhrase(m *core.Modifier) string {
+	if m.AllStatuses || len(m.Statuses) == 0 {
+		return "all Special Conditions"
+	}
+	return "being " + statusList(m.Statuses, "and")
+}
+
+func statusList(statuses []core.StatusCondition, conjunction string) string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = statusName(status)
+	}
+	return list(names, conjunction)
+}
+
+// statusName writes a status as card text does, as in "Asleep".
+func statusName(status core.StatusCondition) string {
+	return capitalize(strings.ToLower(string(status)))
+}
+
+// property writes a property from a trigger, which is upper case, as card
+// text does, as in "a {F} Pokémon".
+func property(p string) string {
+	fields := strings.Fields(strings.ToLower(p))
+	for i, field := range fields {
+		switch {
+		case strings.HasPrefix(field, "{"):
+			fields[i] = strings.ToUpper(field)
+			if field == "{ex}" {
+				fields[i] = field
+			}
+		case field == "pokémon" || field == "evolution":
+			fields[i] = capitalize(field)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// list joins items as English does: "a", "a or b", "a, b, or c".
+func list(items []string, conjunction string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " " + conjunction + " " + items[1]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + ", " + conjunction + " " + items[len(items)-1]
+}
+
+// count writes n of noun, as in "a card" or "3 cards".
+func count(n int, noun string) string {
+	if n <= 1 {
+		return "a " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func article(noun string) string {
+	if noun != "" && strings.ContainsRune("aeiouAEIOU", rune(noun[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+// words turns an identifier such as "ZERO_RETREAT_COST" into "zero retreat
+// cost".
+func words(identifier string) string {
+	return strings.ToLower(strings.ReplaceAll(identifier, "_", " "))
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	return strings.ToUpper(string(r[0])) + string(r[1:])
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// The accessors below return an empty value for missing conditions, so
+// renderers can read them without nil checks.
+
+func filter(c *core.Conditions) *core.Filter {
+	if c.Filter == nil {
+		return &core.Filter{}
+	}
+	return c.Filter
+}
+
+func modifier(c *core.Conditions) *core.Modifier {
+	if c.Modifier == nil {
+		return &core.Modifier{}
+	}
+	return c.Modifier
+}
+
+func energy(c *core.Conditions) *core.Energy {
+	if c.Energy == nil {
+		return &core.Energy{}
+	}
+	return c.Energy
+}
+
+func scaling(c *core.Conditions) *core.Scaling {
+	if c.Scaling == nil {
+		return &core.Scaling{}
+	}
+	return c.Scaling
+}
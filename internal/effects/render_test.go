@@ -0,0 +1,60 @@
+package effects
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderRoundTrip(t *testing.T) {
+	// Each text parses to effects that render back to the same text.
+	texts := []string{
+		"Heal 30 damage from this Pokémon.",
+		"Flip a coin. If heads, your opponent's Active Pokémon is now Paralyzed.",
+		"Flip a coin. If heads, this attack does 30 more damage.",
+		"Flip 2 coins. If both of them are heads, this attack does 80 more damage.",
+		"Flip a coin until you get tails. This attack does 30 more damage for each heads.",
+		"Discard 2 {R} Energy from this Pokémon.",
+		"Take a {G} Energy from your Energy Zone and attach it to 1 of your Benched {G} Pokémon.",
+		"Once during your turn, you may take a {L} Energy from your Energy Zone and attach it to this Pokémon.",
+		"If this Pokémon has at least 2 extra {W} Energy attached, this attack does 60 more damage.",
+		"This attack also does 10 damage to each of your opponent's Benched Pokémon.",
+		"During your opponent's next turn, this Pokémon takes −20 damage from attacks.",
+		"Change the type of the next Energy that will be generated for your opponent to 1 of the following at random: {G}, {R}, {W}, {L}, {P}, {F}, {D}, or {M}.",
+		"If your opponent's Active Pokémon is a {F} Pokémon, this attack does 30 more damage.",
+		"Discard 2 {R} Energy from this Pokémon. This attack does 80 damage to 1 of your opponent's Pokémon.",
+		"This attack does 30 damage to 1 of your opponent's Benched Pokémon.",
+		"Take 2 {M} Energy from your Energy Zone and attach it to 1 of your Benched Pokémon.",
+	}
+	for _, text := range texts {
+		if got := RenderAll(ParseText(text).Effects); got != text {
+			t.Errorf("RenderAll(ParseText(%q)) = %q", text, got)
+		}
+	}
+}
+
+func TestMissingFacts(t *testing.T) {
+	names := []string{"Uxie", "Azelf", "Will"}
+	tests := []struct {
+		text, rendered string
+		want           []string
+	}{
+		// Wording, symbols, "Benched" and reminder text don't matter.
+		{"Switch out your opponent’s Active Pokémon to the Bench. (Your opponent chooses the new Active Pokémon.)",
+			"Switch out your opponent's Active Pokémon to the Bench.", nil},
+		{"Put 1 random {G} Pokémon from your deck into your hand.", "Put a random Grass Pokémon from your deck into your hand.", nil},
+		{"During your opponent's next turn, the Defending Pokémon can't attack.",
+			"During your opponent's next turn, your opponent's Active Pokémon can't attack.", nil},
+		// Dropped requirements, names and numbers do.
+		{"You can use this attack only if you have Uxie and Azelf on your Bench. Discard all Energy from this Pokémon.",
+			"Discard all Energy from this Pokémon.", []string{"Uxie", "Azelf", "bench"}},
+		{"Flip 4 coins. This attack does 40 damage for each heads. If at least 2 of them are heads, your opponent's Active Pokémon is now Poisoned.",
+			"Flip 4 coins. This attack does 40 more damage for each heads.", []string{"2", "active", "poisoned"}},
+		// Names are matched with their case.
+		{"Any Special Conditions already affecting that Pokémon will not be chosen.", "", []string{"special condition"}},
+	}
+	for _, tt := range tests {
+		if got := MissingFacts(tt.text, tt.rendered, names); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MissingFacts(%q, %q) = %q, want %q", tt.text, tt.rendered, got, tt.want)
+		}
+	}
+}
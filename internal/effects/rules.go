@@ -0,0 +1,679 @@
+package effects
+
+import (
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+// defaultRulesJSON is the rule table the parser uses unless told otherwise.
+//
+//go:embed rules.json
+var defaultRulesJSON []byte
+
+// RuleSet is an ordered table of effect rules. The first rule whose pattern
+// matches an effect text decides its effects.
+//
+// Rules are data rather than code so that supporting new card wording only
+// means editing rules.json, or a copy of it passed to `genomon process
+// -rules`. Each rule has:
+//
+//   - name: a unique label, used in reports.
+//   - scope: optional; "trainer" for a rule that parses the whole text of a
+//     Trainer card, which is written from the player's point of view ("1
+//     of your Pokémon") rather than the attacking Pokémon's. Trainer rules
+//     are tried before the others on Trainer card text, and only there.
+//   - pattern: a Go regular expression, matched anywhere in the text.
+//   - priority: rules with a higher priority are tried first; rules with the
+//     same priority are tried in file order. Defaults to 0.
+//   - each: optional; an expression ending in split or list, repeating the
+//     effects once per element of the list.
+//   - effects: templates with a type, and optionally a target, status,
+//     amount, conditions and a fixed description. Conditions are laid out
+//     as core.Conditions is in JSON, and values that come out empty are
+//     left out. Descriptions otherwise default to the matched text. An
+//     effect with a "when" expression is only produced if it isn't empty,
+//     as for an optional capture group.
+//
+// Any string value in a template that starts with "$" is an expression: "$N"
+// for capture group N, or "$each" for the current element of each, followed
+// by any number of "|"-separated filters:
+//
+//   - int: convert to an integer; the rule doesn't apply if this fails.
+//   - upper: convert to upper case.
+//   - replace:OLD>NEW: replace every OLD with NEW.
+//   - split:SEP: split into a list.
+//   - trimsuffix:S: remove a trailing S.
+//   - list: split a list written in English, such as "A, B, and C".
+//   - pokemon: decode a phrase naming Pokémon, such as "{G} Pokémon",
+//     "Stage 2 Pokémon" or "Garchomp or Togekiss", into a filter; plain
+//     "Pokémon" gives none. pokemon:PLAYER also sets the filter's player.
+//
+// Filters after split or list apply to each element of the list.
+type RuleSet struct {
+	rules  []*Rule
+	digest string // Identifies the rules file the table was loaded from
+}
+
+// Rule is one entry in a RuleSet.
+type Rule struct {
+	Name     string           `json:"name"`
+	Scope    string           `json:"scope,omitempty"`
+	Pattern  string           `json:"pattern"`
+	Priority int              `json:"priority,omitempty"`
+	Each     string           `json:"each,omitempty"`
+	Effects  []EffectTemplate `json:"effects"`
+
+	re *regexp.Regexp
+}
+
+// ScopeTrainer is the scope of rules for Trainer card text.
+const ScopeTrainer = "trainer"
+
+// EffectTemplate describes one effect a rule produces.
+type EffectTemplate struct {
+	When        string                 `json:"when,omitempty"` // An expression; the effect is only produced if it isn't empty
+	Type        core.EffectType        `json:"type"`
+	Target      core.TargetType        `json:"target,omitempty"`
+	Status      string                 `json:"status,omitempty"`
+	Amount      interface{}            `json:"amount,omitempty"`     // An integer or an expression
+	Conditions  map[string]interface{} `json:"conditions,omitempty"` // A core.Conditions that may hold expressions
+	Description string                 `json:"description,omitempty"`
+}
+
+// ruleFile is the layout of a rules file.
+type ruleFile struct {
+	Rules []*Rule `json:"rules"`
+}
+
+// rules is the rule table Parse uses.
+var rules = mustLoadRules(defaultRulesJSON)
+
+func mustLoadRules(data []byte) *RuleSet {
+	rs, err := LoadRules(data)
+	if err != nil {
+		panic(fmt.Sprintf("effects: invalid embedded rules: %v", err))
+	}
+	return rs
+}
+
+// DefaultRules returns the rule table built into the parser.
+func DefaultRules() *RuleSet {
+	return mustLoadRules(defaultRulesJSON)
+}
+
+// LoadRules parses a rule table, checking every pattern and expression.
+func LoadRules(data []byte) (*RuleSet, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	decoder.DisallowUnknownFields()
+	var file ruleFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(file.Rules))
+	for i, rule := range file.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("rule %q is defined more than once", rule.Name)
+		}
+		names[rule.Name] = true
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+	}
+
+	// A stable sort keeps file order among rules of equal priority.
+	slices.SortStableFunc(file.Rules, func(a, b *Rule) int { return b.Priority - a.Priority })
+	sum := sha256.Sum256(data)
+	return &RuleSet{rules: file.Rules, digest: hex.EncodeToString(sum[:6])}, nil
+}
+
+// LoadRulesFile reads a rule table from path.
+func LoadRulesFile(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := LoadRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rs, nil
+}
+
+// SetRules replaces the rule table used by Parse and the functions built on
+// it.
+func SetRules(rs *RuleSet) {
+	rules = rs
+}
+
+// Rules returns the rules in the order they are tried.
+func (rs *RuleSet) Rules() []*Rule {
+	return rs.rules
+}
+
+// Match returns the effects of the first rule that applies to text, or nil
+// if none does.
+func (rs *RuleSet) Match(text string) []core.Effect {
+	parsed := rs.match(text)
+	for i := range parsed {
+		parsed[i].Provenance = nil
+	}
+	return parsed
+}
+
+// match is Match, leaving the provenance of each effect for the caller to
+// complete or remove.
+func (rs *RuleSet) match(text string) []core.Effect {
+	return rs.matchScope("", text)
+}
+
+// matchScope is match for the rules of the given scope.
+func (rs *RuleSet) matchScope(scope, text string) []core.Effect {
+	for _, rule := range rs.rules {
+		if rule.Scope != scope {
+			continue
+		}
+		if parsed, ok := rule.apply(text); ok {
+			return parsed
+		}
+	}
+	return nil
+}
+
+// Matching returns every rule that applies to text, in the order they're
+// tried; the first is the one Match uses.
+func (rs *RuleSet) Matching(text string) []*Rule {
+	return rs.matching("", text)
+}
+
+// matching is Matching for the rules of the given scope.
+func (rs *RuleSet) matching(scope, text string) []*Rule {
+	var matching []*Rule
+	for _, rule := range rs.rules {
+		if rule.Scope != scope {
+			continue
+		}
+		if _, ok := rule.apply(text); ok {
+			matching = append(matching, rule)
+		}
+	}
+	return matching
+}
+
+// compile checks the rule and prepares its pattern.
+func (r *Rule) compile() error {
+	if r.Scope != "" && r.Scope != ScopeTrainer {
+		return fmt.Errorf("unknown scope %q", r.Scope)
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return err
+	}
+	r.re = re
+	if len(r.Effects) == 0 {
+		return fmt.Errorf("no effects")
+	}
+
+	check := func(value interface{}) error {
+		return walkValue(value, func(s string) error {
+			if strings.HasPrefix(s, "$each") && r.Each == "" {
+				return fmt.Errorf("%q refers to $each without an each list", s)
+			}
+			return checkExpression(s, re.NumSubexp())
+		})
+	}
+	if r.Each != "" {
+		if err := checkExpression(r.Each, re.NumSubexp()); err != nil {
+			return err
+		}
+		if strings.HasPrefix(r.Each, "$each") {
+			return fmt.Errorf("each can't refer to itself")
+		}
+		filters := strings.Split(r.Each, "|")
+		if last, _, _ := strings.Cut(filters[len(filters)-1], ":"); last != "split" && last != "list" {
+			return fmt.Errorf("each %q doesn't end in split or list", r.Each)
+		}
+	}
+	for i := range r.Effects {
+		effect := &r.Effects[i]
+		if effect.Type == "" {
+			return fmt.Errorf("effect %d has no type", i+1)
+		}
+		effect.Amount = normalizeValue(effect.Amount)
+		effect.Conditions, _ = normalizeValue(effect.Conditions).(map[string]interface{})
+		for _, value := range []interface{}{effect.When, effect.Status, effect.Amount, effect.Conditions} {
+			if err := check(value); err != nil {
+				return err
+			}
+		}
+		if effect.Conditions != nil {
+			if _, err := decodeConditions(placeholders(effect.Conditions)); err != nil {
+				return fmt.Errorf("effect %d conditions: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// apply evaluates the rule against text.
+func (r *Rule) apply(text string) ([]core.Effect, bool) {
+	matches := r.re.FindStringSubmatch(text)
+	if matches == nil {
+		return nil, false
+	}
+
+	elements := []interface{}{nil}
+	if r.Each != "" {
+		value, err := evaluate(r.Each, matches, nil)
+		if err != nil {
+			return nil, false
+		}
+		list, ok := value.([]string)
+		if !ok {
+			return nil, false
+		}
+		elements = nil
+		for _, element := range list {
+			elements = append(elements, element)
+		}
+	}
+
+	var parsed []core.Effect
+	for _, element := range elements {
+		for _, template := range r.Effects {
+			if template.When != "" {
+				when, err := evaluate(template.When, matches, element)
+				if err != nil {
+					return nil, false
+				}
+				if isEmpty(when) {
+					continue
+				}
+			}
+			effect, err := template.instantiate(matches, element)
+			if err != nil {
+				return nil, false
+			}
+			if effect.Description == "" {
+				effect.Description = text
+			}
+			effect.Provenance = newProvenance(r.Name, matches)
+			parsed = append(parsed, effect)
+		}
+	}
+	return parsed, true
+}
+
+// instantiate fills in the template from a match.
+func (t *EffectTemplate) instantiate(matches []string, element interface{}) (core.Effect, error) {
+	effect := core.Effect{Type: t.Type, Target: t.Target, Description: t.Description}
+
+	status, err := resolve(t.Status, matches, element)
+	if err != nil {
+		return effect, err
+	}
+	if status, ok := status.(string); ok {
+		effect.Status = core.StatusCondition(status)
+	}
+
+	amount, err := resolve(t.Amount, matches, element)
+	if err != nil {
+		return effect, err
+	}
+	if amount != nil {
+		amount, ok := amount.(int)
+		if !ok {
+			return effect, fmt.Errorf("amount is not an integer")
+		}
+		effect.Amount = amount
+	}
+
+	if t.Conditions != nil {
+		conditions, err := resolve(t.Conditions, matches, element)
+		if err != nil {
+			return effect, err
+		}
+		if conditions, ok := dropEmpty(conditions); ok {
+			if effect.Conditions, err = decodeConditions(conditions); err != nil {
+				return effect, err
+			}
+		}
+	}
+	return effect, nil
+}
+
+// dropEmpty removes the empty values from a resolved template value, such
+// as a filter decoded from plain "Pokémon" or an optional capture group that
+// didn't match, along with the objects that are left empty. It reports
+// whether anything is left.
+func dropEmpty(value interface{}) (interface{}, bool) {
+	if value, ok := value.(map[string]interface{}); ok {
+		for k, v := range value {
+			if v, ok := dropEmpty(v); ok {
+				value[k] = v
+			} else {
+				delete(value, k)
+			}
+		}
+	}
+	return value, !isEmpty(value)
+}
+
+// isEmpty reports whether a resolved template value holds nothing.
+func isEmpty(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case bool:
+		return !value
+	case []string:
+		return len(value) == 0
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+// decodeConditions converts a resolved conditions template to
+// core.Conditions, rejecting fields it doesn't have.
+func decodeConditions(value interface{}) (*core.Conditions, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var conditions core.Conditions
+	if err := decoder.Decode(&conditions); err != nil {
+		return nil, err
+	}
+	return &conditions, nil
+}
+
+// placeholders replaces each expression in a template value with a value of
+// the type it evaluates to, so the template can be checked before any text
+// is matched.
+func placeholders(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		if !strings.HasPrefix(value, "$") {
+			return value
+		}
+		_, filters, _ := strings.Cut(value, "|")
+		// "C" stands for any text, and is also an energy symbol.
+		var placeholder interface{} = "C"
+		split := false
+		for _, filter := range strings.Split(filters, "|") {
+			name, _, _ := strings.Cut(filter, ":")
+			switch name {
+			case "split", "list":
+				split = true
+			case "int":
+				placeholder = 0
+			case "pokemon":
+				placeholder = nil
+			}
+		}
+		if split {
+			return []interface{}{placeholder}
+		}
+		return placeholder
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, v := range value {
+			out[i] = placeholders(v)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			out[k] = placeholders(v)
+		}
+		return out
+	}
+	return value
+}
+
+// resolve evaluates every expression in a template value, copying it.
+func resolve(value interface{}, matches []string, element interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		if strings.HasPrefix(value, "$") {
+			return evaluate(value, matches, element)
+		}
+		return value, nil
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		allStrings := true
+		for i, v := range value {
+			resolved, err := resolve(v, matches, element)
+			if err != nil {
+				return nil, err
+			}
+			_, isString := resolved.(string)
+			allStrings = allStrings && isString
+			out[i] = resolved
+		}
+		if allStrings {
+			strs := make([]string, len(out))
+			for i, v := range out {
+				strs[i] = v.(string)
+			}
+			return strs, nil
+		}
+		return out, nil
+	case []string:
+		return slices.Clone(value), nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			resolved, err := resolve(v, matches, element)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolved
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+// evaluate computes an expression such as "$1|int" against a match.
+func evaluate(expr string, matches []string, element interface{}) (interface{}, error) {
+	source, filters, _ := strings.Cut(expr, "|")
+	var value interface{}
+	if source == "$each" {
+		value = element
+	} else {
+		n, err := strconv.Atoi(source[1:])
+		if err != nil || n >= len(matches) {
+			return nil, fmt.Errorf("bad capture reference %q", source)
+		}
+		value = matches[n]
+	}
+	if filters == "" {
+		return value, nil
+	}
+
+	for _, filter := range strings.Split(filters, "|") {
+		name, arg, _ := strings.Cut(filter, ":")
+		var err error
+		switch name {
+		case "split":
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("can't split a list")
+			}
+			value = strings.Split(s, arg)
+		case "int":
+			value, err = mapStrings(value, func(s string) (interface{}, error) { return strconv.Atoi(s) })
+		case "upper":
+			value, err = mapStrings(value, func(s string) (interface{}, error) { return strings.ToUpper(s), nil })
+		case "replace":
+			old, replacement, _ := strings.Cut(arg, ">")
+			value, err = mapStrings(value, func(s string) (interface{}, error) { return strings.ReplaceAll(s, old, replacement), nil })
+		case "trimsuffix":
+			value, err = mapStrings(value, func(s string) (interface{}, error) { return strings.TrimSuffix(s, arg), nil })
+		case "list":
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("can't split a list")
+			}
+			value = []string{}
+			if s != "" {
+				value = pokemonNameSplitRegex.Split(s, -1)
+			}
+		case "pokemon":
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("can't decode a list of Pokémon")
+			}
+			value, err = filterValue(pokemonFilter(s), arg)
+		default:
+			return nil, fmt.Errorf("unknown filter %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// filterValue converts filter, for the given player if one is set, to the
+// value of a template's filter, or nil if there is no filter.
+func filterValue(filter *core.Filter, player string) (interface{}, error) {
+	if player != "" {
+		if filter == nil {
+			filter = &core.Filter{}
+		}
+		filter.Player = player
+	}
+	if filter == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	var value map[string]interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+// mapStrings applies f to a string, or to each string in a list.
+func mapStrings(value interface{}, f func(string) (interface{}, error)) (interface{}, error) {
+	switch value := value.(type) {
+	case string:
+		return f(value)
+	case []string:
+		var out []interface{}
+		allStrings := true
+		for _, s := range value {
+			v, err := f(s)
+			if err != nil {
+				return nil, err
+			}
+			_, isString := v.(string)
+			allStrings = allStrings && isString
+			out = append(out, v)
+		}
+		if !allStrings {
+			return out, nil
+		}
+		strs := make([]string, len(out))
+		for i, v := range out {
+			strs[i] = v.(string)
+		}
+		return strs, nil
+	}
+	return nil, fmt.Errorf("can't filter %T", value)
+}
+
+// checkExpression validates an expression without a match to evaluate it
+// against.
+func checkExpression(expr string, groups int) error {
+	source, filters, _ := strings.Cut(expr, "|")
+	if source != "$each" {
+		n, err := strconv.Atoi(strings.TrimPrefix(source, "$"))
+		if err != nil || n < 0 || n > groups {
+			return fmt.Errorf("%q refers to a capture group the pattern doesn't have", expr)
+		}
+	}
+	if filters == "" {
+		return nil
+	}
+	for _, filter := range strings.Split(filters, "|") {
+		name, _, _ := strings.Cut(filter, ":")
+		switch name {
+		case "split", "int", "upper", "replace", "trimsuffix", "list", "pokemon":
+		default:
+			return fmt.Errorf("%q uses unknown filter %q", expr, name)
+		}
+	}
+	return nil
+}
+
+// walkValue calls f for each expression in a template value.
+func walkValue(value interface{}, f func(string) error) error {
+	switch value := value.(type) {
+	case string:
+		if strings.HasPrefix(value, "$") {
+			return f(value)
+		}
+	case []interface{}:
+		for _, v := range value {
+			if err := walkValue(v, f); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for _, v := range value {
+			if err := walkValue(v, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeValue converts decoded JSON numbers to int, or float64 when they
+// aren't whole, matching the values the parser has always produced.
+func normalizeValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			return int(n)
+		}
+		f, _ := value.Float64()
+		return f
+	case []interface{}:
+		for i, v := range value {
+			value[i] = normalizeValue(v)
+		}
+	case map[string]interface{}:
+		for k, v := range value {
+			value[k] = normalizeValue(v)
+		}
+	}
+	return value
+}
@@ -0,0 +1,112 @@
+package effects
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+func TestRuleSetMatch(t *testing.T) {
+	rs, err := LoadRules([]byte(`{"rules": [
+		{"name": "status list", "pattern": "is now (.*)\\.", "each": "$1|replace: and >, |split:, ",
+		 "effects": [{"type": "APPLY_STATUS", "target": "OPPONENT_ACTIVE", "status": "$each|upper"}]},
+		{"name": "heal", "pattern": "Heal (\\d+) damage from (\\w+)", "priority": 1,
+		 "effects": [{"type": "HEAL", "amount": "$1|int", "conditions": {"coinFlip": {"chance": 0.5}, "filter": {"names": ["$2"], "types": ["G", "Fire"]}, "modifier": {"hits": 2}}}]}
+	]}`))
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+
+	// The higher-priority rule is tried first, even though it comes second.
+	got := rs.Match("Heal 30 damage from it. It is now Asleep.")
+	want := []core.Effect{{
+		Type:   core.EffectHeal,
+		Amount: 30,
+		Conditions: &core.Conditions{
+			CoinFlip: &core.CoinFlip{Chance: 0.5},
+			Filter:   &core.Filter{Names: []string{"it"}, Types: []core.EnergyType{core.EnergyGrass, core.EnergyFire}},
+			Modifier: &core.Modifier{Hits: 2},
+		},
+		Description: "Heal 30 damage from it. It is now Asleep.",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Match = %#v, want %#v", got, want)
+	}
+
+	got = rs.Match("Your opponent's Active Pokémon is now Poisoned and Burned.")
+	if len(got) != 2 || got[0].Status != core.StatusPoisoned || got[1].Status != core.StatusBurned {
+		t.Errorf("each rule = %+v", got)
+	}
+
+	if got := rs.Match("Draw a card."); got != nil {
+		t.Errorf("unmatched text = %+v, want nil", got)
+	}
+}
+
+func TestRuleSetTrainerScope(t *testing.T) {
+	rs, err := LoadRules([]byte(`{"rules": [
+		{"name": "heal", "pattern": "Heal (\\d+) damage", "effects": [{"type": "HEAL", "target": "SELF", "amount": "$1|int"}]},
+		{"name": "trainer heal", "scope": "trainer", "pattern": "^Heal (\\d+) damage from 1 of your (.+?)(?:, and it recovers from being (.+?))?\\.$",
+		 "effects": [
+			{"type": "HEAL", "amount": "$1|int", "conditions": {"filter": "$2|pokemon"}},
+			{"when": "$3", "type": "RECOVER_STATUS", "conditions": {"filter": "$2|pokemon:SELF", "modifier": {"statuses": "$3|list|upper"}}}
+		 ]}
+	]}`))
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+
+	// Trainer rules only parse Trainer card text.
+	text := "Heal 30 damage from 1 of your Pokémon."
+	if got := rs.Match(text); len(got) != 1 || got[0].Target != core.TargetSelf {
+		t.Errorf("Match = %+v, want the heal rule's effect", got)
+	}
+
+	// Plain "Pokémon" gives no filter, which leaves no conditions, and the
+	// recovery isn't there without its optional group.
+	want := []core.Effect{{Type: core.EffectHeal, Amount: 30, Description: text}}
+	if got := rs.matchScope(ScopeTrainer, text); !reflect.DeepEqual(stripProvenance(got), want) {
+		t.Errorf("trainer match = %+v, want %+v", got, want)
+	}
+
+	text = "Heal 60 damage from 1 of your {G} Pokémon, and it recovers from being Asleep, Paralyzed, and Confused."
+	want = []core.Effect{
+		{Type: core.EffectHeal, Amount: 60, Conditions: &core.Conditions{Filter: &core.Filter{Type: core.EnergyGrass}}, Description: text},
+		{Type: core.EffectRecoverStatus, Conditions: &core.Conditions{
+			Filter:   &core.Filter{Player: "SELF", Type: core.EnergyGrass},
+			Modifier: &core.Modifier{Statuses: []core.StatusCondition{core.StatusAsleep, core.StatusParalyzed, core.StatusConfused}},
+		}, Description: text},
+	}
+	if got := rs.matchScope(ScopeTrainer, text); !reflect.DeepEqual(stripProvenance(got), want) {
+		t.Errorf("trainer match = %+v, want %+v", got, want)
+	}
+}
+
+func stripProvenance(parsed []core.Effect) []core.Effect {
+	for i := range parsed {
+		parsed[i].Provenance = nil
+	}
+	return parsed
+}
+
+func TestLoadRulesRejectsInvalidRules(t *testing.T) {
+	for _, data := range []string{
+		`{"rules": [{"name": "bad pattern", "pattern": "(", "effects": [{"type": "HEAL"}]}]}`,
+		`{"rules": [{"name": "no effects", "pattern": "x"}]}`,
+		`{"rules": [{"name": "missing group", "pattern": "x", "effects": [{"type": "HEAL", "amount": "$1|int"}]}]}`,
+		`{"rules": [{"name": "bad filter", "pattern": "(x)", "effects": [{"type": "HEAL", "status": "$1|lower"}]}]}`,
+		`{"rules": [{"name": "twice", "pattern": "x", "effects": [{"type": "HEAL"}]}, {"name": "twice", "pattern": "y", "effects": [{"type": "HEAL"}]}]}`,
+		`{"rules": [{"name": "typo", "patern": "x", "effects": [{"type": "HEAL"}]}]}`,
+		`{"rules": [{"name": "unknown condition", "pattern": "x", "effects": [{"type": "HEAL", "conditions": {"who": "it"}}]}]}`,
+		`{"rules": [{"name": "unknown energy", "pattern": "x", "effects": [{"type": "HEAL", "conditions": {"energy": {"type": "X"}}}]}]}`,
+		`{"rules": [{"name": "unknown scope", "scope": "tool", "pattern": "x", "effects": [{"type": "HEAL"}]}]}`,
+		`{"rules": [{"name": "missing when group", "pattern": "x", "effects": [{"when": "$1", "type": "HEAL"}]}]}`,
+		`{"rules": [{"name": "mistyped condition", "pattern": "(x)", "effects": [{"type": "HEAL", "conditions": {"modifier": {"hits": "$1"}}}]}]}`,
+		`{"rules": [{"name": "each not a list", "pattern": "(x)", "each": "$1|upper", "effects": [{"type": "HEAL"}]}]}`,
+	} {
+		if _, err := LoadRules([]byte(data)); err == nil {
+			t.Errorf("LoadRules(%s) succeeded", data)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package effects
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+// Trainer card text is written from the player's point of view ("1 of your
+// Pokémon") rather than the attacking Pokémon's, so it is parsed by the rules
+// with the trainer scope in rules.json before falling back to the rest. These
+// patterns only pick out the parts of the text that the rules then parse.
+var (
+	trainerRequirementRegex = regexp.MustCompile(`^You can use this card only if (.+?)\.\s+`)
+	trainerChooseOneRegex   = regexp.MustCompile(`^Choose 1:\s*\n`)
+
+	// Phrases naming the Pokémon an effect applies to, decoded by the
+	// pokemon filter of the rules
+	stagePokemonRegex      = regexp.MustCompile(`^(?:(Basic|Stage 1|Stage 2) )?(?:{([A-Z])} )?Pokémon$`)
+	evolvesFromRegex       = regexp.MustCompile(`^Pokémon that evolves? from (.+)$`)
+	pokemonNameSplitRegex  = regexp.MustCompile(`,? (?:or|and) |, `)
+	damagedSuffix          = " that has damage on it"
+	attachedEnergySuffixRe = regexp.MustCompile(` that has any {([A-Z])} Energy attached$`)
+)
+
+// ParseTrainer parses the effect text of a Trainer card: an Item, Supporter,
+// Pokémon Tool or Fossil. Wording shared with attacks and abilities falls back
+// to Parse. The text is matched once normalised with Normalize, and every
+// effect is described by the original text.
+func ParseTrainer(text string) []core.Effect {
+	return parseTrainerText(text, recordProvenance)
+}
+
+// parseTrainerText parses trainer text for ParseTrainer, with provenance if
+// withProvenance is set.
+func parseTrainerText(text string, withProvenance bool) []core.Effect {
+	text = strings.TrimSpace(text)
+	n := Normalize(text)
+	parsed := parseTrainer(n.Text)
+	for i := range parsed {
+		parsed[i].Description = text
+	}
+	n.attribute(parsed, withProvenance)
+	return parsed
+}
+
+// parseTrainer parses normalised trainer text.
+func parseTrainer(text string) []core.Effect {
+	// "Choose 1:" cards list their options as separate paragraphs.
+	if options, ok := trainerOptions(text); ok {
+		var parsed []core.Effect
+		for i, option := range options {
+			for _, effect := range parseTrainer(option) {
+				effect.Conditions = withConditions(effect.Conditions)
+				effect.Conditions.Choice = i + 1
+				parsed = append(parsed, effect)
+			}
+		}
+		return parsed
+	}
+
+	body, requirement := trainerBody(text)
+	parsed := rules.matchScope(ScopeTrainer, body)
+	if parsed == nil {
+		parsed = parseText(body).Effects
+	}
+	for i := range parsed {
+		if requirement != "" {
+			parsed[i].Conditions = withConditions(parsed[i].Conditions)
+			if parsed[i].Conditions.Requirement == nil {
+				parsed[i].Conditions.Requirement = &core.Requirement{}
+			}
+			parsed[i].Conditions.Requirement.Text = requirement
+		}
+	}
+	return parsed
+}
+
+// trainerOptions splits the normalised text of a "Choose 1:" card into its
+// options, reporting whether it is one.
+func trainerOptions(text string) ([]string, bool) {
+	loc := trainerChooseOneRegex.FindStringIndex(text)
+	if loc == nil {
+		return nil, false
+	}
+	return strings.Split(text[loc[1]:], "\n\n"), true
+}
+
+// trainerBody returns the part of normalised trainer text that the trainer
+// rules match, on a single line, and the usage requirement that can precede
+// any effect, if there is one.
+func trainerBody(text string) (body, requirement string) {
+	// Match on a single line; some cards break their text over several.
+	body = strings.ReplaceAll(text, "\n\n", " ")
+	if matches := trainerRequirementRegex.FindStringSubmatch(body); len(matches) > 1 {
+		return body[len(matches[0]):], matches[1]
+	}
+	return body, ""
+}
+
+// trainerBodies returns every text the trainer rules match when parsing
+// normalised trainer text: each option of a "Choose 1:" card, or the body.
+func trainerBodies(text string) []string {
+	options, ok := trainerOptions(text)
+	if !ok {
+		options = []string{text}
+	}
+	bodies := make([]string, len(options))
+	for i, option := range options {
+		bodies[i], _ = trainerBody(option)
+	}
+	return bodies
+}
+
+// pokemonFilter decodes a phrase naming which Pokémon an effect applies to,
+// such as "{G} Pokémon", "Basic Pokémon", "Stage 2 Pokémon", "Ultra Beasts",
+// "Pokémon that evolve from Eevee" or a list of names like "Garchomp or
+// Togekiss". Plain "Pokémon" gives no filter.
+func pokemonFilter(phrase string) *core.Filter {
+	var filter core.Filter
+
+	if rest, ok := strings.CutSuffix(phrase, damagedSuffix); ok {
+		filter.Condition = "DAMAGED"
+		phrase = rest
+	}
+	if matches := attachedEnergySuffixRe.FindStringSubmatch(phrase); len(matches) > 1 {
+		filter.Energy = energyType(matches[1])
+		phrase = strings.TrimSuffix(phrase, matches[0])
+	}
+
+	switch {
+	case stagePokemonRegex.MatchString(phrase):
+		matches := stagePokemonRegex.FindStringSubmatch(phrase)
+		filter.Stage = matches[1]
+		if matches[2] != "" {
+			filter.Type = energyType(matches[2])
+		}
+	case evolvesFromRegex.MatchString(phrase):
+		filter.EvolvesFrom = evolvesFromRegex.FindStringSubmatch(phrase)[1]
+	case phrase == "Ultra Beast" || phrase == "Ultra Beasts":
+		filter.Subtype = "Ultra Beast"
+	default:
+		filter.Names = pokemonNameSplitRegex.Split(phrase, -1)
+	}
+
+	if reflect.ValueOf(filter).IsZero() {
+		return nil
+	}
+	return &filter
+}
+
+// energyType converts an energy symbol such as "R" captured from card text.
+// An unknown symbol is kept as it is, for the process command to reject.
+func energyType(symbol string) core.EnergyType {
+	if t, err := core.ParseEnergyType(symbol); err == nil {
+		return t
+	}
+	return core.EnergyType(symbol)
+}
+
+// withConditions returns conditions, allocating them if needed.
+func withConditions(conditions *core.Conditions) *core.Conditions {
+	if conditions == nil {
+		return &core.Conditions{}
+	}
+	return conditions
+}
@@ -0,0 +1,117 @@
+package effects
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+func TestParseTrainer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []core.Effect
+	}{
+		{
+			name: "Fossil",
+			text: "Play this card as if it were a 40-HP Basic {C} Pokémon.\nAt any time during your turn, you may discard this card from play.\nThis card can't retreat.",
+			want: []core.Effect{{
+				Type:   core.EffectPlayAsBasic,
+				Amount: 40,
+				Conditions: &core.Conditions{
+					Energy:   &core.Energy{Type: core.EnergyColorless},
+					Modifier: &core.Modifier{CantRetreat: true, DiscardAnyTime: true},
+				},
+			}},
+		},
+		{
+			name: "Tool healing at the end of the turn",
+			text: "At the end of your turn, if the Pokémon this card is attached to is in the Active Spot, heal 10 damage from that Pokémon.",
+			want: []core.Effect{{
+				Type:   core.EffectToolAttachment,
+				Target: core.TargetAttached,
+				Amount: 10,
+				Conditions: &core.Conditions{
+					Trigger:     &core.Trigger{Event: core.TriggerEndOfTurn},
+					Requirement: &core.Requirement{Location: core.ZoneActive},
+					Modifier:    &core.Modifier{Effect: "HEAL"},
+				},
+			}},
+		},
+		{
+			name: "Tool boosting HP",
+			text: "The Pokémon this card is attached to gets +20 HP.",
+			want: []core.Effect{{
+				Type:   core.EffectToolAttachment,
+				Target: core.TargetAttached,
+				Amount: 20,
+				Conditions: &core.Conditions{
+					Modifier: &core.Modifier{Effect: "BUFF_HP"},
+				},
+			}},
+		},
+		{
+			name: "both players shuffle and draw",
+			text: "Each player shuffles the cards in their hand into their deck, then draws that many cards.",
+			want: []core.Effect{{
+				Type: core.EffectShuffleHandAndDraw,
+				Conditions: &core.Conditions{
+					Scaling: &core.Scaling{By: "HAND_SIZE"},
+					Filter:  &core.Filter{Player: "BOTH"},
+				},
+			}},
+		},
+		{
+			name: "Choose 1",
+			text: "Choose 1:\n\nDuring this turn, attacks used by your Pokémon that evolve from Eevee do +10 damage to your opponent's Active Pokémon.\n\nHeal 20 damage from each of your Pokémon that evolves from Eevee.",
+			want: []core.Effect{
+				{
+					Type:   core.EffectBuffDamage,
+					Target: core.TargetOpponentActive,
+					Amount: 10,
+					Conditions: &core.Conditions{
+						Duration: core.DurationThisTurn,
+						Filter:   &core.Filter{EvolvesFrom: "Eevee"},
+						Choice:   1,
+					},
+				},
+				{
+					Type:   core.EffectHeal,
+					Target: core.TargetAllFriendly,
+					Amount: 20,
+					Conditions: &core.Conditions{
+						Filter: &core.Filter{EvolvesFrom: "Eevee"},
+						Choice: 2,
+					},
+				},
+			},
+		},
+		{
+			name: "usage requirement",
+			text: "You can use this card only if you have Araquanid in play. Switch in 1 of your opponent's Benched Pokémon to the Active Spot.",
+			want: []core.Effect{{
+				Type:   core.EffectForceSwitch,
+				Target: core.TargetBenchedOpponent,
+				Conditions: &core.Conditions{
+					Requirement: &core.Requirement{Text: "you have Araquanid in play"},
+					Modifier:    &core.Modifier{PlayerChooses: true},
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseTrainer(tt.text)
+			for i := range got {
+				if got[i].Description != tt.text {
+					t.Errorf("effect %d described as %q, want the card text", i, got[i].Description)
+				}
+				got[i].Description = ""
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTrainer(%q) =\n %+v\nwant\n %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
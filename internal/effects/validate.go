@@ -0,0 +1,238 @@
+package effects
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// Severity is how sure a validation Warning is that an effect was misparsed.
+type Severity string
+
+const (
+	// SeverityError marks an effect that can't be right for its card, such
+	// as a Special Condition that doesn't exist.
+	SeverityError Severity = "error"
+	// SeverityWarning marks an effect that is unusual for its card and worth
+	// checking against the card text, such as discarding Energy of a type
+	// the card neither is nor needs.
+	SeverityWarning Severity = "warning"
+)
+
+// Warning is a parsed effect that doesn't fit the card it was parsed from.
+type Warning struct {
+	Severity Severity        `json:"severity"`
+	Card     string          `json:"card"`           // The card's ID
+	CardName string          `json:"cardName"`       // The card's name
+	Name     string          `json:"name,omitempty"` // The attack or ability, or empty for a Trainer card's text
+	Effect   core.EffectType `json:"effect,omitempty"`
+	Message  string          `json:"message"`
+}
+
+// Validator checks parsed effects against the card they belong to, which
+// the parser never sees: it parses text alone, so it can't tell that
+// "discard 2 {R} Energy" is odd on a Water Pokémon, or that a Trainer card
+// has no "this Pokémon" to heal.
+type Validator struct {
+	names   map[string]bool // Every card name in the pool
+	attacks map[string]bool // Every attack name in the pool
+}
+
+// NewValidator returns a Validator that checks the names effects refer to
+// against the cards in pool.
+func NewValidator(pool []tcgdex.Card) *Validator {
+	v := &Validator{names: make(map[string]bool), attacks: make(map[string]bool)}
+	for _, card := range pool {
+		v.names[card.Name] = true
+		for _, attack := range card.Attacks {
+			v.attacks[attack.Name] = true
+		}
+	}
+	return v
+}
+
+// Validate checks the parsed effects of card against its types, stage,
+// attack costs and the card pool, returning a warning for each effect that
+// doesn't fit, errors first.
+func (v *Validator) Validate(card core.Card) []Warning {
+	var warnings []Warning
+	warn := func(severity Severity, name string, effect core.EffectType, format string, args ...any) {
+		warnings = append(warnings, Warning{
+			Severity: severity,
+			Card:     card.ID,
+			CardName: card.Name,
+			Name:     name,
+			Effect:   effect,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if card.EvolveFrom != "" && !v.names[card.EvolveFrom] {
+		warn(SeverityWarning, "", "", "evolves from %s, which isn't in the card pool", card.EvolveFrom)
+	}
+
+	check := func(name string, cost []core.EnergyType, parsed []core.Effect) {
+		for _, effect := range parsed {
+			warnEffect := func(severity Severity, format string, args ...any) {
+				warn(severity, name, effect.Type, format, args...)
+			}
+			v.checkStatuses(effect, warnEffect)
+			v.checkDuration(effect, warnEffect)
+			v.checkNames(effect, warnEffect)
+			if card.IsTrainer() {
+				checkTrainer(card, effect, warnEffect)
+			} else {
+				checkPokemon(card, cost, effect, warnEffect)
+			}
+		}
+	}
+	for _, ability := range card.Abilities {
+		check(ability.Name, nil, named(card.ParsedAbilities, ability.Name))
+	}
+	for i, attack := range card.Attacks {
+		check(attack.Name, card.Cost(i), named(card.ParsedAttacks, attack.Name))
+	}
+	check("", nil, card.ParsedTrainerEffects)
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Severity == SeverityError && warnings[j].Severity != SeverityError
+	})
+	return warnings
+}
+
+// checkStatuses flags Special Conditions that don't exist, which come from a
+// pattern capturing more of the text than the condition's name.
+func (v *Validator) checkStatuses(effect core.Effect, warn func(Severity, string, ...any)) {
+	statuses := []core.StatusCondition{effect.Status}
+	if modifier := conditionsModifier(effect); modifier != nil {
+		statuses = append(statuses, modifier.Statuses...)
+		statuses = append(statuses, modifier.PossibleStatuses...)
+	}
+	for _, status := range statuses {
+		if status != "" && !slices.Contains(specialConditions, status) {
+			warn(SeverityError, "%q isn't a Special Condition", status)
+		}
+	}
+}
+
+var specialConditions = []core.StatusCondition{
+	core.StatusAsleep, core.StatusBurned, core.StatusConfused, core.StatusParalyzed, core.StatusPoisoned,
+}
+
+// checkDuration flags effects whose duration contradicts the turn their text
+// names, as when "during your opponent's next turn" is parsed as the
+// player's own next turn.
+func (v *Validator) checkDuration(effect core.Effect, warn func(Severity, string, ...any)) {
+	if effect.Conditions == nil {
+		return
+	}
+	text := strings.ToLower(Normalize(effect.Description).Text)
+	if effect.Conditions.Duration == core.DurationNextTurn &&
+		strings.Contains(text, "your opponent's next turn") && !strings.Contains(text, "during your next turn") {
+		warn(SeverityWarning, "lasts %s, but the text is about your opponent's next turn", core.DurationNextTurn)
+	}
+}
+
+// checkNames flags card and attack names that no card in the pool has.
+func (v *Validator) checkNames(effect core.Effect, warn func(Severity, string, ...any)) {
+	c := effect.Conditions
+	if c == nil {
+		return
+	}
+	var names []string
+	if c.Trigger != nil {
+		names = append(names, c.Trigger.Name)
+		if c.Trigger.AttackName != "" && !v.attacks[c.Trigger.AttackName] {
+			warn(SeverityWarning, "refers to the attack %s, which no card in the pool has", c.Trigger.AttackName)
+		}
+	}
+	if c.Requirement != nil {
+		names = append(names, c.Requirement.InPlay...)
+	}
+	if c.Scaling != nil {
+		names = append(names, c.Scaling.Names...)
+	}
+	for _, filter := range []*core.Filter{c.Filter, c.Opponent, c.Attacker} {
+		if filter != nil {
+			names = append(names, filter.Names...)
+			names = append(names, filter.ExcludeName, filter.EvolvesFrom)
+		}
+	}
+	for _, name := range names {
+		if name != "" && !v.names[name] {
+			warn(SeverityWarning, "refers to %s, which isn't in the card pool", name)
+		}
+	}
+}
+
+// checkTrainer flags Trainer card effects that only make sense on a Pokémon.
+func checkTrainer(card core.Card, effect core.Effect, warn func(Severity, string, ...any)) {
+	if effect.Target == core.TargetSelf && card.TrainerKind() != tcgdex.TrainerFossil {
+		warn(SeverityError, "targets %s, but a Trainer card has no Pokémon of its own", core.TargetSelf)
+	}
+}
+
+// checkPokemon flags a Pokémon's effects that don't fit its stage, or that
+// involve Energy of a type the Pokémon neither is nor needs for the attack.
+func checkPokemon(card core.Card, cost []core.EnergyType, effect core.Effect, warn func(Severity, string, ...any)) {
+	c := effect.Conditions
+	if c == nil {
+		return
+	}
+	if card.EvolutionStage() == 0 && c.Trigger != nil &&
+		(c.Trigger.Event == core.TriggerEvolvedThisTurn || c.Trigger.Event == core.TriggerOnEvolve) {
+		warn(SeverityError, "needs this Pokémon to evolve, but it is a Basic Pokémon")
+	}
+
+	for _, t := range ownEnergyTypes(effect) {
+		if t == core.EnergyColorless || slices.Contains(card.PokemonTypes(), t) || slices.Contains(cost, t) {
+			continue
+		}
+		if cost != nil {
+			warn(SeverityWarning, "involves %s Energy attached to this Pokémon, which is neither its type nor in the attack's cost", t)
+		} else {
+			warn(SeverityWarning, "involves %s Energy attached to this Pokémon, which isn't its type", t)
+		}
+	}
+}
+
+// ownEnergyTypes returns the types of Energy attached to the Pokémon itself
+// that the effect discards, moves, counts or checks for.
+func ownEnergyTypes(effect core.Effect) []core.EnergyType {
+	c := effect.Conditions
+	var types []core.EnergyType
+	energy := func() {
+		if c.Energy != nil {
+			types = append(types, c.Energy.Type)
+			types = append(types, c.Energy.Types...)
+		}
+	}
+	switch {
+	case effect.Type == core.EffectDiscardEnergy && effect.Target == core.TargetSelf:
+		energy()
+	case effect.Type == core.EffectMoveEnergy && c.Source != nil && c.Source.Zone == core.ZoneSelf:
+		energy()
+	case c.Trigger != nil && c.Trigger.Event == core.TriggerSelfHasTypedEnergy:
+		energy()
+	case c.CoinFlip != nil && c.CoinFlip.FlipsPer == "SELF_ATTACHED_ENERGY_TYPED":
+		energy()
+	}
+	if c.Scaling != nil && c.Scaling.By == "SELF_ATTACHED_ENERGY" {
+		types = append(types, c.Scaling.Type)
+	}
+	if c.Requirement != nil {
+		types = append(types, c.Requirement.EnergyType)
+	}
+	return slices.DeleteFunc(types, func(t core.EnergyType) bool { return t == "" })
+}
+
+func conditionsModifier(effect core.Effect) *core.Modifier {
+	if effect.Conditions == nil {
+		return nil
+	}
+	return effect.Conditions.Modifier
+}
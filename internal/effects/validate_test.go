@@ -0,0 +1,118 @@
+package effects
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+func TestValidate(t *testing.T) {
+	pool := []tcgdex.Card{
+		{Name: "Horsea"},
+		{Name: "Seadra", EvolveFrom: "Horsea"},
+		{Name: "Latios", Attacks: []tcgdex.Attack{{Name: "Luster Purge"}}},
+	}
+	v := NewValidator(pool)
+
+	tests := []struct {
+		name string
+		card tcgdex.Card
+		want []string // The severity and message of each warning
+	}{
+		{
+			name: "Energy of another type",
+			card: tcgdex.Card{Category: "Pokemon", Stage: "Stage1", Types: []string{"Water"}, EvolveFrom: "Horsea", Attacks: []tcgdex.Attack{{
+				Name: "Steam Blast", Cost: []string{"Water", "Colorless"}, Effect: "Discard 2 {R} Energy from this Pokémon.",
+			}}},
+			want: []string{"warning: involves Fire Energy attached to this Pokémon, which is neither its type nor in the attack's cost"},
+		},
+		{
+			name: "Energy in the attack's cost",
+			card: tcgdex.Card{Category: "Pokemon", Stage: "Basic", Types: []string{"Water"}, Attacks: []tcgdex.Attack{{
+				Name: "Steam Blast", Cost: []string{"Fire", "Water"}, Effect: "Discard a {R} Energy from this Pokémon.",
+			}}},
+		},
+		{
+			name: "Trainer healing itself",
+			card: tcgdex.Card{Category: "Trainer", TrainerType: "Item", Text: "Heal 30 damage from this Pokémon."},
+			want: []string{"error: targets SELF, but a Trainer card has no Pokémon of its own"},
+		},
+		{
+			name: "Basic Pokémon that evolved",
+			card: tcgdex.Card{Category: "Pokemon", Stage: "Basic", Types: []string{"Water"}, Attacks: []tcgdex.Attack{{
+				Name: "Surprise", Cost: []string{"Water"}, Effect: "If this Pokémon evolved during this turn, this attack does 20 more damage.",
+			}}},
+			want: []string{"error: needs this Pokémon to evolve, but it is a Basic Pokémon"},
+		},
+		{
+			name: "names missing from the pool",
+			card: tcgdex.Card{Category: "Pokemon", Stage: "Stage1", Types: []string{"Psychic"}, EvolveFrom: "Bagon", Attacks: []tcgdex.Attack{
+				{Name: "Team Up", Cost: []string{"Psychic"}, Effect: "If Latias is on your Bench, this attack does 20 more damage."},
+				{Name: "Team Again", Cost: []string{"Psychic"}, Effect: "If Latios is on your Bench, this attack does 20 more damage."},
+			}},
+			want: []string{
+				"warning: evolves from Bagon, which isn't in the card pool",
+				"warning: refers to Latias, which isn't in the card pool",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, _ := ParseCard(tt.card)
+			var got []string
+			for _, warning := range v.Validate(card) {
+				got = append(got, string(warning.Severity)+": "+warning.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStatus(t *testing.T) {
+	// A pattern that captures more than the condition's name.
+	card := core.Card{
+		Card: tcgdex.Card{Name: "Toxicroak", Category: "Pokemon", Stage: "Stage1", Attacks: []tcgdex.Attack{{Name: "Toxic"}}},
+		ParsedAttacks: []core.Effect{{
+			Name:   "Toxic",
+			Type:   core.EffectApplyStatus,
+			Target: core.TargetOpponentActive,
+			Status: "POISONED. DO 20 DAMAGE",
+		}},
+	}
+	warnings := NewValidator(nil).Validate(card)
+	if len(warnings) != 1 || warnings[0].Severity != SeverityError || warnings[0].Message != `"POISONED. DO 20 DAMAGE" isn't a Special Condition` {
+		t.Fatalf("Validate = %+v, want 1 error about the Special Condition", warnings)
+	}
+
+	card.ParsedAttacks[0].Status = core.StatusPoisoned
+	if warnings := NewValidator(nil).Validate(card); len(warnings) != 0 {
+		t.Errorf("Validate = %+v, want no warnings", warnings)
+	}
+}
+
+func TestValidateDuration(t *testing.T) {
+	card := core.Card{
+		Card: tcgdex.Card{Name: "Cubone", Category: "Pokemon", Stage: "Basic", Attacks: []tcgdex.Attack{{Name: "Growl"}}},
+		ParsedAttacks: []core.Effect{{
+			Name:        "Growl",
+			Type:        core.EffectReduceIncomingDamage,
+			Target:      core.TargetOpponentActive,
+			Amount:      20,
+			Conditions:  &core.Conditions{Duration: core.DurationNextTurn},
+			Description: "During your opponent’s next turn, attacks used by the Defending Pokémon do −20 damage.",
+		}},
+	}
+	warnings := NewValidator(nil).Validate(card)
+	if len(warnings) != 1 || warnings[0].Severity != SeverityWarning || warnings[0].Name != "Growl" {
+		t.Fatalf("Validate = %+v, want 1 warning about Growl", warnings)
+	}
+
+	card.ParsedAttacks[0].Conditions.Duration = core.DurationOpponentNextTurn
+	if warnings := NewValidator(nil).Validate(card); len(warnings) != 0 {
+		t.Errorf("Validate = %+v, want no warnings", warnings)
+	}
+}
@@ -0,0 +1,237 @@
+package effects
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/cpritch/genomon/internal/core"
+)
+
+// Loss is a card text whose parsed effects don't say everything it does:
+// rendering them back to text with Render leaves out some of its facts.
+type Loss struct {
+	Card     string   `json:"card"`           // The card's ID
+	CardName string   `json:"cardName"`       // The card's name
+	Name     string   `json:"name,omitempty"` // The attack or ability, or empty for a Trainer card's text
+	Text     string   `json:"text"`
+	Rendered string   `json:"rendered"`
+	Missing  []string `json:"missing"` // Facts in Text that Rendered doesn't have
+}
+
+// Verify renders the parsed effects of each attack, ability and Trainer card
+// text back to text and compares the result with the original, returning
+// every text that loses information in parsing.
+//
+// The comparison is by facts rather than wording: the numbers, energy
+// types, Special Conditions, game terms and card and attack names each text
+// mentions, after both are normalised. A fact counts once however often it
+// appears, so the comparison can miss an effect that is parsed once but
+// happens twice.
+func Verify(cards []core.Card) []Loss {
+	names := poolNames(cards)
+	var losses []Loss
+	check := func(card core.Card, name, text string, parsed []core.Effect) {
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		rendered := RenderAll(parsed)
+		if missing := MissingFacts(text, rendered, names); len(missing) > 0 {
+			losses = append(losses, Loss{
+				Card:     card.ID,
+				CardName: card.Name,
+				Name:     name,
+				Text:     text,
+				Rendered: rendered,
+				Missing:  missing,
+			})
+		}
+	}
+	for _, card := range cards {
+		for _, ability := range card.Abilities {
+			check(card, ability.Name, ability.Effect, named(card.ParsedAbilities, ability.Name))
+		}
+		for _, attack := range card.Attacks {
+			check(card, attack.Name, attack.Effect, named(card.ParsedAttacks, attack.Name))
+		}
+		check(card, "", card.Text, card.ParsedTrainerEffects)
+	}
+	return losses
+}
+
+// MissingFacts returns the facts in text that rendered doesn't have, in the
+// order text mentions them. names are the card and attack names to look
+// for.
+func MissingFacts(text, rendered string, names []string) []string {
+	have := Facts(rendered, names)
+	var missing []string
+	for _, fact := range Facts(text, names) {
+		if !slices.Contains(have, fact) {
+			missing = append(missing, fact)
+		}
+	}
+	return missing
+}
+
+// Facts returns what text says that a faithful parse must keep, in the
+// order it first mentions them: numbers, energy types, Special
+// Conditions, game terms and any of names. The number 1 isn't counted, as
+// card text writes it as "a" as often as not.
+func Facts(text string, names []string) []string {
+	normal := " " + comparable(text) + " "
+	type found struct {
+		at   int
+		fact string
+	}
+	var facts []found
+	add := func(fact string, at int) {
+		for i, f := range facts {
+			if f.fact == fact {
+				if at < f.at {
+					facts[i].at = at
+				}
+				return
+			}
+		}
+		facts = append(facts, found{at, fact})
+	}
+
+	for _, match := range numberPattern.FindAllStringIndex(normal, -1) {
+		if number := normal[match[0]:match[1]]; number != "1" {
+			add(number, match[0])
+		}
+	}
+	for _, terms := range [][]string{energyTerms, statusTerms, factTerms} {
+		for _, term := range terms {
+			if at := strings.Index(normal, " "+comparable(term)+" "); at >= 0 {
+				add(term, at)
+			}
+		}
+	}
+	// Names keep their case, so that the Trainer "Will" isn't found in "will
+	// not be chosen".
+	named := " " + strings.Join(strings.Fields(comparableNames(text)), " ") + " "
+	for _, name := range names {
+		if slices.Contains(factTerms, strings.ToLower(name)) {
+			continue // An attack called "Attach" is found as the term
+		}
+		word := " " + strings.Join(strings.Fields(comparableNames(name)), " ") + " "
+		if at := strings.Index(named, word); word != "  " && at >= 0 {
+			add(strings.TrimSpace(word), at)
+		}
+	}
+
+	slices.SortStableFunc(facts, func(a, b found) int { return a.at - b.at })
+	out := make([]string, len(facts))
+	for i, f := range facts {
+		out[i] = f.fact
+	}
+	return out
+}
+
+var numberPattern = regexp.MustCompile(`\d+`)
+
+var (
+	energyTerms = func() []string {
+		terms := make([]string, len(core.EnergyTypes))
+		for i, t := range core.EnergyTypes {
+			terms[i] = string(t)
+		}
+		return terms
+	}()
+	statusTerms = []string{"asleep", "burned", "confused", "paralyzed", "poisoned", "special condition"}
+
+	// factTerms are the game terms whose loss changes what an effect does.
+	factTerms = []string{
+		"heads", "tails", "bench", "active", "hand", "deck", "discard pile", "energy zone",
+		"discard", "draw", "heal", "shuffle", "switch", "attach", "move", "retreat", "evolve",
+		"knocked out", "random", "each", "all", "both", "instead", "can't", "prevent",
+		"next turn", "this turn", "tool", "supporter", "item", "ex", "ultra beast",
+		"basic", "stage 1", "stage 2", "point", "points",
+	}
+)
+
+// comparable normalises card text so that the same fact is spelled the same
+// way wherever it appears: lower case, "{R}" as "fire", "Benched" as
+// "bench", "the Defending Pokémon" as "your opponent's Active Pokémon", and
+// no punctuation or accents.
+func comparable(text string) string {
+	text = symbolPattern.ReplaceAllStringFunc(text, func(symbol string) string {
+		if t, err := core.ParseEnergyType(symbol); err == nil {
+			return " " + string(t) + " "
+		}
+		return symbol
+	})
+	text = strings.ToLower(reminderPattern.ReplaceAllString(text, " "))
+	text = comparableReplacer.Replace(text)
+	return strings.Join(strings.Fields(comparableNames(text)), " ")
+}
+
+// comparableNames strips the punctuation and accents from text, keeping
+// the letters of names such as "Nidoran♂" and "Type: Null".
+func comparableNames(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ReplaceAll(text, "’", "'") {
+		switch {
+		case unicode.IsLetter(r) && r < unicode.MaxASCII, unicode.IsDigit(r), r == '\'', r == '♂', r == '♀':
+			b.WriteRune(r)
+		case r == 'é':
+			b.WriteRune('e')
+		case r == 'É':
+			b.WriteRune('E')
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
+var (
+	symbolPattern = regexp.MustCompile(`\{[A-Za-z]\}`)
+	// reminderPattern matches reminder text, such as "(Your opponent chooses
+	// the new Active Pokémon.)", which explains a rule rather than adding to
+	// the effect.
+	reminderPattern = regexp.MustCompile(`\([^)]*\)`)
+)
+
+var comparableReplacer = strings.NewReplacer(
+	"’", "'",
+	"the defending pokémon", "your opponent's active pokémon",
+	"benched", "bench",
+	"discarded", "discard",
+	"special conditions", "special condition",
+	"all of your", "each of your",
+	"can not", "can't",
+	"cannot", "can't",
+)
+
+// poolNames returns the names of every card and attack in cards.
+func poolNames(cards []core.Card) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, card := range cards {
+		add(card.Name)
+		for _, attack := range card.Attacks {
+			add(attack.Name)
+		}
+	}
+	return names
+}
+
+// named returns the effects parsed from the attack or ability called name.
+func named(parsed []core.Effect, name string) []core.Effect {
+	var out []core.Effect
+	for _, effect := range parsed {
+		if effect.Name == name {
+			out = append(out, effect)
+		}
+	}
+	return out
+}
@@ -0,0 +1,217 @@
+// Package imagestore keeps downloaded card artwork in a content-addressed
+// directory so tools can render cards without network access.
+//
+// Image files are stored under objects/, named by the SHA-256 of their
+// contents, so identical artwork shared between cards (reprints, promos) is
+// stored once and a re-download of an unchanged image never rewrites a file.
+// manifest.json maps each card ID to its object along with the HTTP validators
+// needed to cheaply check the image for changes on the next sync.
+package imagestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const manifestFile = "manifest.json"
+
+// Entry describes the stored image of one card.
+type Entry struct {
+	URL          string    `json:"url"`
+	Path         string    `json:"path"` // Relative to the store directory, with forward slashes
+	SHA256       string    `json:"sha256"`
+	Size         int       `json:"size"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+}
+
+// Manifest is the index of a store, written to manifest.json.
+type Manifest struct {
+	Images map[string]Entry `json:"images"` // Card ID -> stored image
+}
+
+// Store is a content-addressed image directory. It is safe for concurrent use.
+type Store struct {
+	dir string
+
+	mu       sync.Mutex
+	manifest Manifest
+}
+
+// Open opens the store in dir, creating it if needed and loading its manifest.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create image store %s: %w", dir, err)
+	}
+
+	s := &Store{dir: dir, manifest: Manifest{Images: make(map[string]Entry)}}
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode image manifest: %w", err)
+	}
+	if s.manifest.Images == nil {
+		s.manifest.Images = make(map[string]Entry)
+	}
+	return s, nil
+}
+
+// Dir returns the store's directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Entry returns the stored image of a card. It reports false if the card has
+// no image or its object file has gone missing, so the image is re-downloaded.
+func (s *Store) Entry(cardID string) (Entry, bool) {
+	s.mu.Lock()
+	entry, ok := s.manifest.Images[cardID]
+	s.mu.Unlock()
+	if !ok {
+		return Entry{}, false
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, filepath.FromSlash(entry.Path))); err != nil {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Put stores data as the image of a card downloaded from url and records it in
+// the manifest. The object is only written if no image with the same content
+// is stored yet. It reports whether the card's image changed.
+func (s *Store) Put(cardID, url string, data []byte, etag, lastModified string) (Entry, bool, error) {
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	entry := Entry{
+		URL:          url,
+		Path:         path.Join("objects", digest[:2], digest+path.Ext(url)),
+		SHA256:       digest,
+		Size:         len(data),
+		ETag:         etag,
+		LastModified: lastModified,
+		FetchedAt:    time.Now().UTC(),
+	}
+
+	if err := s.writeObject(entry.Path, data); err != nil {
+		return Entry{}, false, err
+	}
+
+	s.mu.Lock()
+	previous, existed := s.manifest.Images[cardID]
+	s.manifest.Images[cardID] = entry
+	s.mu.Unlock()
+	return entry, !existed || previous.Path != entry.Path, nil
+}
+
+// Touch records that a card's image was revalidated as unchanged, keeping any
+// refreshed validators.
+func (s *Store) Touch(cardID, etag, lastModified string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.manifest.Images[cardID]
+	if !ok {
+		return
+	}
+	entry.ETag, entry.LastModified = etag, lastModified
+	entry.FetchedAt = time.Now().UTC()
+	s.manifest.Images[cardID] = entry
+}
+
+// Save writes the manifest to disk.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.manifest, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode image manifest: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(s.dir, manifestFile), data)
+}
+
+// Prune deletes stored objects no longer referenced by the manifest and
+// returns how many were removed.
+func (s *Store) Prune() (int, error) {
+	s.mu.Lock()
+	referenced := make(map[string]bool, len(s.manifest.Images))
+	for _, entry := range s.manifest.Images {
+		referenced[entry.Path] = true
+	}
+	s.mu.Unlock()
+
+	var unreferenced []string
+	objects := filepath.Join(s.dir, "objects")
+	err := filepath.WalkDir(objects, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		if !referenced[filepath.ToSlash(rel)] {
+			unreferenced = append(unreferenced, p)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("failed to scan image store: %w", err)
+	}
+
+	sort.Strings(unreferenced)
+	for i, p := range unreferenced {
+		if err := os.Remove(p); err != nil {
+			return i, fmt.Errorf("failed to prune image: %w", err)
+		}
+	}
+	return len(unreferenced), nil
+}
+
+// writeObject writes data to the object at rel unless it already exists.
+func (s *Store) writeObject(rel string, data []byte) error {
+	objectPath := filepath.Join(s.dir, filepath.FromSlash(rel))
+	if _, err := os.Stat(objectPath); err == nil {
+		return nil // Content-addressed, so an existing object is already correct
+	}
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0755); err != nil {
+		return fmt.Errorf("failed to create image directory: %w", err)
+	}
+	return writeFileAtomic(objectPath, data)
+}
+
+// writeFileAtomic writes data to path via a temporary file so readers never
+// see a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// LegacyCard is a card in the hand-curated cleaned-cards.json schema that
+// predates the TCGdex sync. It covers the first few sets only.
+type LegacyCard struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Rarity      string         `json:"rarity"`      // e.g. "Common", "Art Rare"
+	Type        string         `json:"type"`        // Energy type for Pokémon, otherwise Supporter, Item or Pokemon Tool
+	Stage       string         `json:"stage"`       // Empty for trainers
+	Requires    *string        `json:"requires"`    // The Pokémon this evolves from
+	RetreatCost float64        `json:"retreatCost"` // A few entries hold typos such as 2.1
+	Attacks     []LegacyAttack `json:"attacks"`
+	Ability     *LegacyAbility `json:"ability"` // Also holds the text of trainer cards
+	Health      int            `json:"health"`
+	Dex         string         `json:"dex"` // Comma-separated booster codes, e.g. "A1C,A1P"
+	Rule        *string        `json:"rule"`
+	Weakness    *string        `json:"weakness"`
+}
+
+// LegacyAttack is an attack in the legacy schema.
+type LegacyAttack struct {
+	Name    string         `json:"name"`
+	Cost    []string       `json:"cost"`
+	Damage  int            `json:"damage"` // Modifiers such as "+" or "×" were dropped
+	Text    string         `json:"text"`
+	Effects []LegacyEffect `json:"effects"`
+}
+
+// LegacyAbility is an ability, or the text of a trainer card, in the legacy
+// schema. Some tool cards give only the text as a plain string.
+type LegacyAbility struct {
+	Title   string         `json:"title"`
+	Text    string         `json:"text"`
+	Effects []LegacyEffect `json:"effects"`
+}
+
+// UnmarshalJSON accepts either an ability object or a bare text string.
+func (a *LegacyAbility) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*a = LegacyAbility{Text: text}
+		return nil
+	}
+	type plain LegacyAbility
+	return json.Unmarshal(data, (*plain)(a))
+}
+
+// LegacyEffect is a pre-parsed effect in the legacy schema. Only the fields
+// that map onto core.Effect are kept.
+type LegacyEffect struct {
+	Type   string          `json:"type"` // snake_case, e.g. "status_condition"
+	Status string          `json:"status"`
+	Amount json.RawMessage `json:"amount"` // Usually a number, sometimes a description like "all"
+}
+
+// legacyRarities maps legacy rarity names to the TCGdex ones.
+var legacyRarities = map[string]string{
+	"Common":         "One Diamond",
+	"Uncommon":       "Two Diamond",
+	"Rare":           "Three Diamond",
+	"Double Rare":    "Four Diamond",
+	"Art Rare":       "One Star",
+	"Super Rare":     "Two Star",
+	"Immersive Rare": "Three Star",
+	"Ultra Rare":     "Crown",
+}
+
+// legacyTrainerTypes maps legacy trainer card types to TCGdex trainer types.
+var legacyTrainerTypes = map[string]string{
+	"Supporter":    "Supporter",
+	"Item":         "Item",
+	"Pokemon Tool": "Tool",
+}
+
+// legacyEffectTypes maps the legacy effect types that have an unambiguous
+// equivalent onto our effect types. The rest are imported as EffectUnknown.
+var legacyEffectTypes = map[string]core.EffectType{
+	"heal":             core.EffectHeal,
+	"heal_multiple":    core.EffectHeal,
+	"draw_card":        core.EffectDraw,
+	"draw_cards":       core.EffectDraw,
+	"status_condition": core.EffectApplyStatus,
+	"apply_status":     core.EffectApplyStatus,
+	"discard_energy":   core.EffectDiscardEnergy,
+	"search":           core.EffectSearchDeck,
+	"search_deck":      core.EffectSearchDeck,
+	"copy_attack":      core.EffectCopyAttack,
+	"move_energy":      core.EffectMoveEnergy,
+	"attach_energy":    core.EffectAttachEnergy,
+	"energy_attach":    core.EffectAttachEnergy,
+	"self_damage":      core.EffectRecoilDamage,
+	"reveal_hand":      core.EffectRevealHand,
+	"peek_deck":        core.EffectLookAtDeck,
+	"move_damage":      core.EffectMoveDamage,
+	"halve_hp":         core.EffectDamageHalveHP,
+}
+
+// legacyDamagePrefix matches the printed damage, such as "30+" or "50x", that
+// the legacy schema repeats at the start of some attack texts.
+var legacyDamagePrefix = regexp.MustCompile(`^(\d+)([+x×])\s+`)
+
+// ImportLegacy converts cards in the cleaned-cards.json schema. Legacy effects
+// become parsed effects, with types that have no clear equivalent kept as
+// EffectUnknown so they are never mistaken for a real disagreement.
+func ImportLegacy(data []byte) ([]core.Card, error) {
+	var legacy []LegacyCard
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, err
+	}
+
+	cards := make([]core.Card, 0, len(legacy))
+	for _, lc := range legacy {
+		card, err := lc.convert()
+		if err != nil {
+			return nil, fmt.Errorf("card %s: %w", lc.ID, err)
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// convert maps a legacy card onto our card model.
+func (lc *LegacyCard) convert() (core.Card, error) {
+	setID, localID, ok := cutLast(lc.ID, "-")
+	if !ok {
+		return core.Card{}, fmt.Errorf("malformed card ID")
+	}
+
+	card := core.Card{
+		Card: tcgdex.Card{
+			ID:      lc.ID,
+			LocalID: localID,
+			Name:    lc.Name,
+			Rarity:  legacyRarities[lc.Rarity],
+			Set:     tcgdex.Set{ID: setID},
+		},
+		ParsedAbilities: []core.Effect{},
+		ParsedAttacks:   []core.Effect{},
+	}
+
+	if trainerType, ok := legacyTrainerTypes[lc.Type]; ok {
+		card.Category = tcgdex.CategoryTrainer
+		card.TrainerType = trainerType
+		if lc.Ability != nil {
+			card.Text = lc.Ability.Text
+		}
+		return card, nil
+	}
+
+	card.Category = tcgdex.CategoryPokemon
+	card.HP = lc.Health
+	card.Types = []string{lc.Type}
+	card.Stage = lc.Stage
+	card.Retreat = int(lc.RetreatCost)
+	if lc.Requires != nil {
+		card.EvolveFrom = *lc.Requires
+	}
+	if lc.Weakness != nil && !strings.EqualFold(*lc.Weakness, "none") {
+		card.Weaknesses = []tcgdex.Weakness{{Type: *lc.Weakness, Value: "+20"}}
+	}
+
+	for _, la := range lc.Attacks {
+		attack := tcgdex.Attack{
+			Cost:   la.Cost,
+			Name:   la.Name,
+			Effect: la.Text,
+			Damage: tcgdex.Damage{Base: la.Damage},
+		}
+		if m := legacyDamagePrefix.FindStringSubmatch(la.Text); m != nil {
+			if damage, err := tcgdex.ParseDamage(m[1] + m[2]); err == nil {
+				attack.Damage = damage
+			}
+			attack.Effect = la.Text[len(m[0]):]
+		}
+		card.Attacks = append(card.Attacks, attack)
+		card.ParsedAttacks = append(card.ParsedAttacks, convertLegacyEffects(la.Name, attack.Effect, la.Effects)...)
+	}
+	if lc.Ability != nil {
+		card.Abilities = []tcgdex.Ability{{Name: lc.Ability.Title, Effect: lc.Ability.Text, Type: "Ability"}}
+		card.ParsedAbilities = convertLegacyEffects(lc.Ability.Title, lc.Ability.Text, lc.Ability.Effects)
+	}
+	return card, nil
+}
+
+// convertLegacyEffects maps the legacy effects of one attack or ability.
+func convertLegacyEffects(name, text string, legacy []LegacyEffect) []core.Effect {
+	var converted []core.Effect
+	for _, le := range legacy {
+		effectType, ok := legacyEffectTypes[le.Type]
+		if !ok {
+			effectType = core.EffectUnknown
+		}
+		status := core.StatusCondition(strings.ToUpper(le.Status))
+		switch status {
+		case "", core.StatusPoisoned, core.StatusConfused, core.StatusAsleep, core.StatusBurned, core.StatusParalyzed:
+		default:
+			// Restrictions such as "can't attack" were recorded as statuses.
+			status, effectType = "", core.EffectUnknown
+		}
+		var amount int
+		json.Unmarshal(le.Amount, &amount) // Descriptive amounts have no numeric equivalent
+		converted = append(converted, core.Effect{
+			Name:        name,
+			Type:        effectType,
+			Status:      status,
+			Amount:      amount,
+			Description: text,
+		})
+	}
+	return converted
+}
+
+// cutLast slices s around the last instance of sep, so that set IDs such as
+// "P-A" keep their own dashes.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
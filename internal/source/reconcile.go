@@ -0,0 +1,214 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// Report is the result of reconciling two card sources.
+type Report struct {
+	A        string       `json:"a"` // Source names
+	B        string       `json:"b"`
+	OnlyInA  []string     `json:"onlyInA,omitempty"` // IDs of cards missing from B
+	OnlyInB  []string     `json:"onlyInB,omitempty"` // IDs of cards missing from A
+	Compared int          `json:"compared"`          // Number of cards present in both sources
+	Cards    []CardReport `json:"cards,omitempty"`   // Cards the sources disagree about
+}
+
+// CardReport lists the disagreements between two sources about one card.
+type CardReport struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Disagreements []Disagreement `json:"disagreements"`
+}
+
+// Disagreement is a single field on which two sources differ.
+type Disagreement struct {
+	Field string `json:"field"` // e.g. "hp", "attack Vine Whip cost"
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+// Reconcile loads cards from both sources and compares every card they have
+// in common on HP, retreat cost, attack costs and damage, and effects.
+func Reconcile(ctx context.Context, a, b CardSource) (*Report, error) {
+	aCards, err := a.Cards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", a.Name(), err)
+	}
+	bCards, err := b.Cards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", b.Name(), err)
+	}
+
+	report := CompareCards(aCards, bCards)
+	report.A, report.B = a.Name(), b.Name()
+	return report, nil
+}
+
+// CompareCards compares two card lists by card ID. Cards are reported in the
+// order they appear in a. Only the first card with a given ID in each list is
+// compared, since older datasets repeat some cards.
+func CompareCards(a, b []core.Card) *Report {
+	bByID := make(map[string]*core.Card, len(b))
+	for i := range b {
+		if _, dup := bByID[b[i].ID]; !dup {
+			bByID[b[i].ID] = &b[i]
+		}
+	}
+
+	report := &Report{}
+	seen := make(map[string]bool, len(a))
+	for i := range a {
+		aCard := &a[i]
+		if seen[aCard.ID] {
+			continue
+		}
+		seen[aCard.ID] = true
+		bCard, ok := bByID[aCard.ID]
+		if !ok {
+			report.OnlyInA = append(report.OnlyInA, aCard.ID)
+			continue
+		}
+
+		report.Compared++
+		if disagreements := compareCard(aCard, bCard); len(disagreements) > 0 {
+			report.Cards = append(report.Cards, CardReport{ID: aCard.ID, Name: aCard.Name, Disagreements: disagreements})
+		}
+	}
+	for _, card := range b {
+		if !seen[card.ID] {
+			seen[card.ID] = true
+			report.OnlyInB = append(report.OnlyInB, card.ID)
+		}
+	}
+	return report
+}
+
+// compareCard lists the fields on which two versions of a card disagree.
+func compareCard(a, b *core.Card) []Disagreement {
+	var out []Disagreement
+	add := func(field, aValue, bValue string) {
+		if aValue != bValue {
+			out = append(out, Disagreement{Field: field, A: aValue, B: bValue})
+		}
+	}
+
+	add("hp", strconv.Itoa(a.HP), strconv.Itoa(b.HP))
+	if a.IsPokemon() && b.IsPokemon() {
+		add("retreat", strconv.Itoa(a.Retreat), strconv.Itoa(b.Retreat))
+	}
+	add("effect", normalizeText(a.Text), normalizeText(b.Text))
+	// Not every source parses Trainer text, so only compare the effects of
+	// cards both sources have parsed.
+	if len(a.ParsedTrainerEffects) > 0 && len(b.ParsedTrainerEffects) > 0 {
+		add("effects", effectTypes(a.ParsedTrainerEffects, a.Name, b.ParsedTrainerEffects), effectTypes(b.ParsedTrainerEffects, a.Name, a.ParsedTrainerEffects))
+	}
+
+	for aIndex, aAttack := range a.Attacks {
+		i := slices.IndexFunc(b.Attacks, func(attack tcgdex.Attack) bool { return sameName(attack.Name, aAttack.Name) })
+		if i < 0 {
+			add("attacks", aAttack.Name, "")
+			continue
+		}
+		bAttack := b.Attacks[i]
+		field := "attack " + aAttack.Name
+		add(field+" cost", formatCost(a.Cost(aIndex)), formatCost(b.Cost(i)))
+		add(field+" damage", strconv.Itoa(aAttack.Damage.Base), strconv.Itoa(bAttack.Damage.Base))
+		add(field+" text", normalizeText(aAttack.Effect), normalizeText(bAttack.Effect))
+		add(field+" effects", effectTypes(a.ParsedAttacks, aAttack.Name, b.ParsedAttacks), effectTypes(b.ParsedAttacks, aAttack.Name, a.ParsedAttacks))
+	}
+	for _, bAttack := range b.Attacks {
+		if !slices.ContainsFunc(a.Attacks, func(attack tcgdex.Attack) bool { return sameName(attack.Name, bAttack.Name) }) {
+			add("attacks", "", bAttack.Name)
+		}
+	}
+
+	for _, aAbility := range a.Abilities {
+		i := slices.IndexFunc(b.Abilities, func(ability tcgdex.Ability) bool { return sameName(ability.Name, aAbility.Name) })
+		if i < 0 {
+			add("abilities", aAbility.Name, "")
+			continue
+		}
+		field := "ability " + aAbility.Name
+		add(field+" text", normalizeText(aAbility.Effect), normalizeText(b.Abilities[i].Effect))
+		add(field+" effects", effectTypes(a.ParsedAbilities, aAbility.Name, b.ParsedAbilities), effectTypes(b.ParsedAbilities, aAbility.Name, a.ParsedAbilities))
+	}
+	for _, bAbility := range b.Abilities {
+		if !slices.ContainsFunc(a.Abilities, func(ability tcgdex.Ability) bool { return sameName(ability.Name, bAbility.Name) }) {
+			add("abilities", "", bAbility.Name)
+		}
+	}
+
+	return out
+}
+
+// effectTypes summarises the effect types parsed for the attack, ability or
+// Trainer card called name, for comparison with the same one in other. Types
+// the other source doesn't know (EffectUnknown on either side) are left out,
+// since one source failing to classify an effect isn't a disagreement about
+// it.
+func effectTypes(parsed []core.Effect, name string, other []core.Effect) string {
+	otherUnknown := false
+	for _, effect := range other {
+		if sameName(effect.Name, name) && effect.Type == core.EffectUnknown {
+			otherUnknown = true
+		}
+	}
+	if otherUnknown {
+		return ""
+	}
+
+	var types []string
+	for _, effect := range parsed {
+		if !sameName(effect.Name, name) || effect.Type == core.EffectUnknown {
+			continue
+		}
+		if !slices.Contains(types, string(effect.Type)) {
+			types = append(types, string(effect.Type))
+		}
+	}
+	slices.Sort(types)
+	return strings.Join(types, ",")
+}
+
+// formatCost lists an energy cost by name in a canonical order, since sources
+// don't agree on the order mixed costs are printed in, or on whether they are
+// written as names or symbols.
+func formatCost(cost []core.EnergyType) string {
+	names := make([]string, len(cost))
+	for i, t := range cost {
+		names[i] = string(t)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ",")
+}
+
+// sameName compares attack or ability names, ignoring case and accents.
+func sameName(a, b string) bool {
+	return normalizeText(a) == normalizeText(b)
+}
+
+// textReplacer folds the spelling differences between sources: accents,
+// typographic quotes and dashes, and energy symbols such as "{G}" that other
+// sources spell out.
+var textReplacer = strings.NewReplacer(
+	"é", "e", "É", "E",
+	"’", "'", "‘", "'", "“", `"`, "”", `"`,
+	"—", "-", "–", "-", "−", "-",
+	"{G}", "Grass", "{R}", "Fire", "{W}", "Water", "{L}", "Lightning", "{P}", "Psychic",
+	"{F}", "Fighting", "{D}", "Darkness", "{M}", "Metal", "{C}", "Colorless",
+	"{ex}", "ex",
+)
+
+// normalizeText prepares text for comparison between sources, folding case,
+// spelling differences and runs of whitespace.
+func normalizeText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(textReplacer.Replace(s)), " "))
+}
@@ -0,0 +1,123 @@
+// Package source loads card data from the places we can get it: the TCGdex
+// API, files written by our own commands, and older datasets such as the
+// legacy cleaned-cards.json. Every source produces cards in the same shape so
+// they can be compared with Reconcile.
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/internal/effects"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// CardSource is a provider of card data.
+type CardSource interface {
+	// Name identifies the source in reports, e.g. a file path or "tcgdex".
+	Name() string
+
+	// Cards loads every card the source has, with parsed effects. Sources
+	// that carry their own parsed effects return those; the rest are parsed
+	// with our effect parser.
+	Cards(ctx context.Context) ([]core.Card, error)
+}
+
+// TCGdex is a CardSource that fetches every card in the client's series from
+// the TCGdex API.
+type TCGdex struct {
+	client *tcgdex.Client
+}
+
+// NewTCGdex returns a source that fetches cards with client.
+func NewTCGdex(client *tcgdex.Client) *TCGdex {
+	return &TCGdex{client: client}
+}
+
+// Name implements CardSource.
+func (s *TCGdex) Name() string {
+	return "tcgdex"
+}
+
+// Cards implements CardSource. Unlike the sync command it has no tolerance
+// for partial data: any set or card that can't be fetched fails the load.
+func (s *TCGdex) Cards(ctx context.Context) ([]core.Card, error) {
+	setIDs, err := s.client.FetchTCGPSetIDsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []core.Card
+	for _, setID := range setIDs {
+		setDetails, err := s.client.FetchSetContext(ctx, setID)
+		if err != nil {
+			return nil, fmt.Errorf("set %s: %w", setID, err)
+		}
+		setCards, err := s.client.FetchCardsInSetContext(ctx, setDetails)
+		if err != nil {
+			return nil, err
+		}
+		for _, card := range setCards {
+			parsed, _ := effects.ParseCard(card)
+			cards = append(cards, parsed)
+		}
+	}
+	return cards, nil
+}
+
+// File is a CardSource that reads a local JSON file. It accepts the raw
+// output of `genomon sync`, the enriched output of `genomon process`, and the
+// legacy cleaned-cards.json schema, telling them apart by their fields.
+type File struct {
+	path string
+}
+
+// NewFile returns a source that reads cards from path.
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// Name implements CardSource.
+func (s *File) Name() string {
+	return s.path
+}
+
+// Cards implements CardSource.
+func (s *File) Cards(ctx context.Context) ([]core.Card, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []core.Card
+	if isLegacy(data) {
+		cards, err = ImportLegacy(data)
+	} else {
+		err = json.Unmarshal(data, &cards)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", s.path, err)
+	}
+
+	// Raw sync output has no parsed effects at all, unlike processed output.
+	for i, card := range cards {
+		if card.ParsedAbilities == nil && card.ParsedAttacks == nil {
+			cards[i], _ = effects.ParseCard(card.Card)
+		}
+	}
+	return cards, nil
+}
+
+// isLegacy reports whether data looks like the legacy cleaned-cards.json
+// schema, which is the only format with a "health" field.
+func isLegacy(data []byte) bool {
+	var probe []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil || len(probe) == 0 {
+		return false
+	}
+	_, ok := probe[0]["health"]
+	return ok
+}
@@ -0,0 +1,137 @@
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/internal/core"
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+const legacyFixture = `[
+  {
+    "id": "A1-022", "name": "Exeggutor", "rarity": "Uncommon", "type": "Grass", "stage": "Stage 1",
+    "requires": "Exeggcute", "retreatCost": 3, "health": 130, "dex": "A1M", "rule": null, "weakness": "Fire",
+    "attacks": [{"name": "Stomp", "cost": ["Grass"], "damage": 30,
+      "text": "30+ Flip a coin. If heads, this attack does 30 more damage.", "effects": [{"type": "coin_flip", "amount": "all"}]}]
+  },
+  {
+    "id": "A2-147", "name": "Giant Cape", "rarity": "Uncommon", "type": "Pokemon Tool",
+    "ability": "The Pokemon this card is attached to gets +20 HP.",
+    "health": 0, "requires": null, "dex": "A2D", "rule": null, "weakness": null
+  }
+]`
+
+func TestFileLoadsLegacyCards(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cleaned-cards.json")
+	if err := os.WriteFile(path, []byte(legacyFixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cards, err := NewFile(path).Cards(context.Background())
+	if err != nil {
+		t.Fatalf("Cards: %v", err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("got %d cards, want 2", len(cards))
+	}
+
+	exeggutor := cards[0]
+	if exeggutor.HP != 130 || exeggutor.Retreat != 3 || exeggutor.EvolveFrom != "Exeggcute" || exeggutor.Rarity != "Two Diamond" {
+		t.Errorf("Exeggutor imported as %+v", exeggutor.Card)
+	}
+	stomp := exeggutor.Attacks[0]
+	if stomp.Damage.String() != "30+" || stomp.Effect != "Flip a coin. If heads, this attack does 30 more damage." {
+		t.Errorf("Stomp imported as damage %q, text %q", stomp.Damage, stomp.Effect)
+	}
+	if len(exeggutor.ParsedAttacks) != 1 || exeggutor.ParsedAttacks[0].Type != core.EffectUnknown {
+		t.Errorf("unmapped legacy effect imported as %+v", exeggutor.ParsedAttacks)
+	}
+
+	cape := cards[1]
+	if !cape.IsTrainer() || cape.TrainerType != "Tool" || cape.Text == "" {
+		t.Errorf("Giant Cape imported as %+v", cape.Card)
+	}
+}
+
+func TestCompareCards(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cleaned-cards.json")
+	if err := os.WriteFile(path, []byte(legacyFixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := NewFile(path).Cards(context.Background())
+	if err != nil {
+		t.Fatalf("Cards: %v", err)
+	}
+
+	current, err := NewFile("../../genomon-cards.json").Cards(context.Background())
+	if err != nil {
+		t.Fatalf("Cards: %v", err)
+	}
+
+	report := CompareCards(legacy, current)
+	if report.Compared != 2 || len(report.OnlyInA) != 0 {
+		t.Fatalf("compared %d cards, %d only in legacy", report.Compared, len(report.OnlyInA))
+	}
+	// The sources spell "Pokémon" differently and only the legacy card
+	// repeats the damage in its text; neither is a disagreement.
+	for _, card := range report.Cards {
+		t.Errorf("unexpected disagreements about %s: %+v", card.ID, card.Disagreements)
+	}
+
+	hp := legacy[0].HP
+	legacy[0].HP = 120
+	report = CompareCards(legacy, current)
+	if len(report.Cards) != 1 || report.Cards[0].Disagreements[0].Field != "hp" {
+		t.Errorf("HP change reported as %+v", report.Cards)
+	}
+
+	// Costs written as symbols agree with the same costs written as names.
+	legacy[0].HP = hp
+	cost := legacy[0].Attacks[0].Cost
+	legacy[0].Attacks[0].Cost = make([]string, len(cost))
+	for i, name := range cost {
+		energy, _ := core.ParseEnergyType(name)
+		legacy[0].Attacks[0].Cost[i] = "{" + energy.Symbol() + "}"
+	}
+	if report = CompareCards(legacy, current); len(report.Cards) != 0 {
+		t.Errorf("cost written as symbols %v reported as %+v", legacy[0].Attacks[0].Cost, report.Cards)
+	}
+}
+
+func TestCompareCardsTrainerEffects(t *testing.T) {
+	potion := func(effects ...core.EffectType) core.Card {
+		card := core.Card{Card: tcgdex.Card{ID: "P-A-001", Name: "Potion", Category: "Trainer", TrainerType: "Item",
+			Text: "Heal 20 damage from 1 of your Pokémon."}}
+		for _, effect := range effects {
+			card.ParsedTrainerEffects = append(card.ParsedTrainerEffects, core.Effect{Name: "Potion", Type: effect})
+		}
+		return card
+	}
+
+	tests := []struct {
+		name string
+		a, b core.Card
+		want []Disagreement
+	}{
+		{"same effects", potion(core.EffectHeal), potion(core.EffectHeal), nil},
+		{"different effects", potion(core.EffectHeal), potion(core.EffectDraw),
+			[]Disagreement{{Field: "effects", A: string(core.EffectHeal), B: string(core.EffectDraw)}}},
+		{"unparsed in one source", potion(core.EffectHeal), potion(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := CompareCards([]core.Card{tt.a}, []core.Card{tt.b})
+			var got []Disagreement
+			for _, card := range report.Cards {
+				got = append(got, card.Disagreements...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("disagreements = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package tcgdex
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache is an on-disk store of API responses keyed by URL. Cached responses
+// are revalidated with conditional requests (If-None-Match / If-Modified-Since),
+// so an unchanged resource costs a 304 instead of a full download.
+type Cache struct {
+	dir string
+}
+
+// cacheEntry is the on-disk representation of a single cached response.
+type cacheEntry struct {
+	URL          string          `json:"url"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	FetchedAt    time.Time       `json:"fetchedAt"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// NewCache opens (creating if necessary) a response cache in dir.
+func NewCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory %s: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// path returns the file used to store the response for url.
+func (c *Cache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the cached entry for url, or nil if there isn't a usable one.
+func (c *Cache) get(url string) *cacheEntry {
+	data, err := os.ReadFile(c.path(url))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return nil
+	}
+	return &entry
+}
+
+// put stores body as the cached response for url, along with the validators
+// from the response headers. Writes go through a temp file so concurrent
+// readers never see a partial entry.
+func (c *Cache) put(url string, header http.Header, body []byte) error {
+	entry := cacheEntry{
+		URL:          url,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		FetchedAt:    time.Now().UTC(),
+		Body:         body,
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(url)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// setConditionalHeaders adds revalidation headers for a cached entry to header.
+func (e *cacheEntry) setConditionalHeaders(header http.Header) {
+	setConditionalHeaders(header, e.ETag, e.LastModified)
+}
+
+// setConditionalHeaders adds If-None-Match and If-Modified-Since headers for
+// whichever validators are known.
+func setConditionalHeaders(header http.Header, etag, lastModified string) {
+	if etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		header.Set("If-Modified-Since", lastModified)
+	}
+}
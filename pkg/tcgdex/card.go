@@ -0,0 +1,103 @@
+package tcgdex
+
+import "strings"
+
+// TrainerKind is the gameplay subtype of a Trainer card.
+type TrainerKind string
+
+const (
+	TrainerUnknown   TrainerKind = ""
+	TrainerItem      TrainerKind = "Item"
+	TrainerSupporter TrainerKind = "Supporter"
+	TrainerTool      TrainerKind = "Tool"
+	// TrainerFossil is an Item that is played as if it were a Basic Pokémon.
+	TrainerFossil TrainerKind = "Fossil"
+)
+
+// Card categories as returned by TCGdex.
+const (
+	CategoryPokemon = "Pokemon"
+	CategoryTrainer = "Trainer"
+)
+
+// IsPokemon reports whether the card is a Pokémon.
+func (c *Card) IsPokemon() bool {
+	return c.Category == CategoryPokemon
+}
+
+// IsTrainer reports whether the card is a Trainer (Item, Supporter, Tool or Fossil).
+func (c *Card) IsTrainer() bool {
+	return c.Category == CategoryTrainer
+}
+
+// IsEx reports whether the card is a Pokémon ex, which gives up extra points
+// when Knocked Out.
+func (c *Card) IsEx() bool {
+	return c.IsPokemon() && (strings.EqualFold(c.Suffix, "EX") || strings.HasSuffix(c.Name, " ex"))
+}
+
+// IsMega reports whether the card is a Mega Evolution Pokémon.
+func (c *Card) IsMega() bool {
+	return c.IsPokemon() && strings.HasPrefix(c.Name, "Mega ")
+}
+
+// HasRuleBox reports whether special rules apply to the card when it is
+// Knocked Out, as for Pokémon ex and Mega Pokémon.
+func (c *Card) HasRuleBox() bool {
+	return c.IsEx() || c.IsMega()
+}
+
+// Points returns how many points the opponent gets for Knocking Out this
+// Pokémon: 3 for a Mega Pokémon ex, 2 for any other Pokémon ex and 1 otherwise.
+func (c *Card) Points() int {
+	switch {
+	case c.IsMega() && c.IsEx():
+		return 3
+	case c.IsEx():
+		return 2
+	}
+	return 1
+}
+
+// IsFossil reports whether the card is a Fossil, an Item played as if it were
+// a Basic Pokémon.
+func (c *Card) IsFossil() bool {
+	return c.IsTrainer() && strings.HasPrefix(c.Text, "Play this card as if it were")
+}
+
+// TrainerKind returns the card's Trainer subtype, or TrainerUnknown for
+// Pokémon and for Trainers whose subtype wasn't recognised.
+func (c *Card) TrainerKind() TrainerKind {
+	if !c.IsTrainer() {
+		return TrainerUnknown
+	}
+	if c.IsFossil() {
+		return TrainerFossil
+	}
+	switch strings.ToLower(c.TrainerType) {
+	case "item":
+		return TrainerItem
+	case "supporter":
+		return TrainerSupporter
+	case "tool", "pokemon tool", "pokémon tool":
+		return TrainerTool
+	}
+	return TrainerUnknown
+}
+
+// EvolutionStage returns 0 for Basic Pokémon, 1 for Stage 1 and 2 for Stage 2,
+// or -1 if the card isn't a Pokémon with a recognised stage.
+func (c *Card) EvolutionStage() int {
+	if !c.IsPokemon() {
+		return -1
+	}
+	switch strings.ReplaceAll(strings.ToLower(c.Stage), " ", "") {
+	case "basic":
+		return 0
+	case "stage1":
+		return 1
+	case "stage2":
+		return 2
+	}
+	return -1
+}
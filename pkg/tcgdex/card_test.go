@@ -0,0 +1,74 @@
+package tcgdex_test
+
+import (
+	"testing"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+func TestIsEx(t *testing.T) {
+	tests := []struct {
+		card tcgdex.Card
+		want bool
+	}{
+		{tcgdex.Card{Name: "Pikachu ex", Category: "Pokemon"}, true},
+		{tcgdex.Card{Name: "Pikachu", Category: "Pokemon", Suffix: "EX"}, true},
+		{tcgdex.Card{Name: "Mega Gyarados ex", Category: "Pokemon"}, true},
+		{tcgdex.Card{Name: "Pikachu", Category: "Pokemon"}, false},
+		{tcgdex.Card{Name: "Exeggutor", Category: "Pokemon"}, false},
+		{tcgdex.Card{Name: "Rocky Helmet ex", Category: "Trainer"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.card.IsEx(); got != tt.want {
+			t.Errorf("%s (%s, suffix %q).IsEx() = %v, want %v", tt.card.Name, tt.card.Category, tt.card.Suffix, got, tt.want)
+		}
+	}
+}
+
+func TestTrainerKind(t *testing.T) {
+	fossil := "Play this card as if it were a 40-HP Basic {C} Pokémon."
+	tests := []struct {
+		category, trainerType, text string
+		want                        tcgdex.TrainerKind
+	}{
+		{"Trainer", "Item", "", tcgdex.TrainerItem},
+		{"Trainer", "Supporter", "", tcgdex.TrainerSupporter},
+		{"Trainer", "supporter", "", tcgdex.TrainerSupporter},
+		{"Trainer", "Tool", "", tcgdex.TrainerTool},
+		{"Trainer", "Pokémon Tool", "", tcgdex.TrainerTool},
+		{"Trainer", "Pokemon Tool", "", tcgdex.TrainerTool},
+		{"Trainer", "Item", fossil, tcgdex.TrainerFossil},
+		{"Trainer", "", fossil, tcgdex.TrainerFossil},
+		{"Trainer", "", "", tcgdex.TrainerUnknown},
+		{"Trainer", "Stadium", "", tcgdex.TrainerUnknown},
+		{"Pokemon", "Item", "", tcgdex.TrainerUnknown},
+	}
+	for _, tt := range tests {
+		card := tcgdex.Card{Category: tt.category, TrainerType: tt.trainerType, Text: tt.text}
+		if got := card.TrainerKind(); got != tt.want {
+			t.Errorf("TrainerKind() of a %s with type %q and text %q = %q, want %q", tt.category, tt.trainerType, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestEvolutionStage(t *testing.T) {
+	tests := []struct {
+		category, stage string
+		want            int
+	}{
+		{"Pokemon", "Basic", 0},
+		{"Pokemon", "Stage1", 1},
+		{"Pokemon", "Stage 1", 1},
+		{"Pokemon", "stage2", 2},
+		{"Pokemon", "Stage 2", 2},
+		{"Pokemon", "", -1},
+		{"Pokemon", "VMAX", -1},
+		{"Trainer", "Basic", -1},
+	}
+	for _, tt := range tests {
+		card := tcgdex.Card{Category: tt.category, Stage: tt.stage}
+		if got := card.EvolutionStage(); got != tt.want {
+			t.Errorf("EvolutionStage() of a %s with stage %q = %d, want %d", tt.category, tt.stage, got, tt.want)
+		}
+	}
+}
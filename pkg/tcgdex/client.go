@@ -1,52 +1,270 @@
 package tcgdex
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	apiBaseURL   = "https://api.tcgdex.net/v2/en"
-	pocketSeries = "tcgp"
+	// DefaultBaseURL is the root of the public TCGdex REST API, without a language.
+	DefaultBaseURL = "https://api.tcgdex.net/v2"
+	// DefaultLanguage is the language card data is fetched in unless overridden.
+	DefaultLanguage = "en"
+	// PocketSeries is the TCGdex series ID for Pokémon TCG Pocket.
+	PocketSeries = "tcgp"
+)
+
+const (
+	// DefaultRequestsPerSecond is the sustained request rate used unless
+	// overridden with WithRateLimit. TCGdex is a free service, so keep it modest.
+	DefaultRequestsPerSecond = 10
+	// DefaultConcurrency is the default maximum number of in-flight card requests.
+	DefaultConcurrency = 4
 )
 
 // Client is a client for interacting with the TCGdex API.
 type Client struct {
-	httpClient *http.Client
+	httpClient  *http.Client
+	baseURL     string
+	language    string
+	series      string
+	limiter     *rateLimiter
+	concurrency int
+	retry       RetryPolicy
+	cache       *Cache
+	progress    ProgressFunc
+	progressMu  sync.Mutex
+}
+
+// Option configures optional behaviour of a Client.
+type Option func(*Client)
+
+// WithBaseURL points the client at a different TCGdex deployment, such as a
+// mirror or a fake server in tests. The URL should not include the language.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithLanguage sets the language card data is fetched in (e.g. "fr", "ja").
+func WithLanguage(language string) Option {
+	return func(c *Client) {
+		c.language = language
+	}
+}
+
+// WithSeries sets which series FetchTCGPSetIDs lists sets for.
+func WithSeries(series string) Option {
+	return func(c *Client) {
+		c.series = series
+	}
+}
+
+// WithHTTPClient replaces the underlying HTTP client, e.g. to change timeouts
+// or transports.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRateLimit limits the client to requestsPerSecond sustained requests,
+// allowing short bursts of up to burst requests. A non-positive rate disables
+// rate limiting entirely.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(requestsPerSecond, burst)
+	}
+}
+
+// WithConcurrency sets the maximum number of card requests that may be in
+// flight at once when fetching a set.
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		if n < 1 {
+			n = 1
+		}
+		c.concurrency = n
+	}
+}
+
+// WithRetryPolicy overrides how transient request failures are retried.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithCache stores responses in cache and revalidates them with conditional
+// requests on subsequent fetches.
+func WithCache(cache *Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
 }
 
 // NewClient creates a new TCGdex API client.
-func NewClient() *Client {
-	return &Client{
+func NewClient(opts ...Option) *Client {
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		baseURL:     DefaultBaseURL,
+		language:    DefaultLanguage,
+		series:      PocketSeries,
+		limiter:     newRateLimiter(DefaultRequestsPerSecond, DefaultConcurrency),
+		concurrency: DefaultConcurrency,
+		retry:       DefaultRetryPolicy,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-// FetchTCGPSetIDs fetches all set IDs belonging to the TCG Pocket series.
-func (c *Client) FetchTCGPSetIDs() ([]string, error) {
-	seriesURL := fmt.Sprintf("%s/series/%s", apiBaseURL, pocketSeries)
-	req, err := http.NewRequest("GET", seriesURL, nil)
+// Language returns the language the client fetches card data in.
+func (c *Client) Language() string {
+	return c.language
+}
+
+// endpoint builds the URL of an API resource in the client's language.
+func (c *Client) endpoint(format string, args ...interface{}) string {
+	return fmt.Sprintf("%s/%s/", c.baseURL, c.language) + fmt.Sprintf(format, args...)
+}
+
+// response is a successful (200) or not-modified (304) HTTP response.
+type response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// get performs a rate-limited GET request with any extra headers, retrying
+// transient failures according to the client's retry policy. Only 200 and 304
+// responses are returned; any other status becomes a *StatusError. The what
+// argument describes the resource for error messages.
+func (c *Client) get(ctx context.Context, url, what string, header http.Header) (*response, error) {
+	maxAttempts := max(c.retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		resp, err := c.tryGet(ctx, url, what, header)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= maxAttempts || !isRetryable(err) {
+			if attempt > 1 {
+				return nil, fmt.Errorf("%w (gave up after %d attempts)", err, attempt)
+			}
+			return nil, err
+		}
+
+		var retryAfter time.Duration
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			retryAfter = statusErr.RetryAfter
+		}
+		delay := c.retry.backoff(attempt, retryAfter)
+		c.report(ProgressEvent{Kind: ProgressRetrying, Resource: what, Attempt: attempt, Delay: delay, Err: err})
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// tryGet makes a single attempt at fetching url.
+func (c *Client) tryGet(ctx context.Context, url, what string, header http.Header) (*response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request for TCGP series: %w", err)
+		return nil, &permanentError{fmt.Errorf("failed to create request for %s: %w", what, err)}
+	}
+	for key, values := range header {
+		req.Header[key] = values
 	}
 
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch TCGP series: %w", err)
+		return nil, fmt.Errorf("failed to fetch %s: %w", what, err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad status code when fetching TCGP series: %d", resp.StatusCode)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", what, err)
+		}
+		return &response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+	case http.StatusNotModified:
+		return &response{StatusCode: resp.StatusCode, Header: resp.Header}, nil
+	}
+
+	statusErr := &StatusError{StatusCode: resp.StatusCode}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		statusErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
 	}
+	return nil, fmt.Errorf("bad status code when fetching %s: %w", what, statusErr)
+}
+
+// getJSON fetches url and decodes the JSON response into v. When the client
+// has a cache, a cached response is revalidated rather than re-downloaded.
+func (c *Client) getJSON(ctx context.Context, url, what string, v interface{}) error {
+	header := http.Header{}
+	var cached *cacheEntry
+	if c.cache != nil {
+		cached = c.cache.get(url)
+		if cached != nil {
+			cached.setConditionalHeaders(header)
+		}
+	}
+
+	resp, err := c.get(ctx, url, what, header)
+	if err != nil {
+		return err
+	}
+
+	body := resp.Body
+	if resp.StatusCode == http.StatusNotModified {
+		if cached == nil {
+			return fmt.Errorf("bad status code when fetching %s: %w", what, &StatusError{StatusCode: resp.StatusCode})
+		}
+		body = cached.Body
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+
+	if c.cache != nil && resp.StatusCode == http.StatusOK {
+		// A failed cache write only costs us a re-download next time.
+		_ = c.cache.put(url, resp.Header, body)
+	}
+
+	return nil
+}
+
+// FetchTCGPSetIDs fetches all set IDs belonging to the TCG Pocket series, or
+// to the series configured with WithSeries.
+func (c *Client) FetchTCGPSetIDs() ([]string, error) {
+	return c.FetchTCGPSetIDsContext(context.Background())
+}
+
+// FetchTCGPSetIDsContext is like FetchTCGPSetIDs but stops early if ctx is done.
+func (c *Client) FetchTCGPSetIDsContext(ctx context.Context) ([]string, error) {
+	seriesURL := c.endpoint("series/%s", c.series)
 
 	var seriesDetails SeriesDetails
-	if err := json.NewDecoder(resp.Body).Decode(&seriesDetails); err != nil {
-		return nil, fmt.Errorf("failed to decode TCGP series details: %w", err)
+	if err := c.getJSON(ctx, seriesURL, "TCGP series", &seriesDetails); err != nil {
+		return nil, err
 	}
 
 	var setIDs []string
@@ -57,69 +275,147 @@ func (c *Client) FetchTCGPSetIDs() ([]string, error) {
 	return setIDs, nil
 }
 
+// FetchSet fetches the details of a set, including the list of cards it contains.
+func (c *Client) FetchSet(setID string) (*SetDetails, error) {
+	return c.FetchSetContext(context.Background(), setID)
+}
+
+// FetchSetContext is like FetchSet but stops early if ctx is done.
+func (c *Client) FetchSetContext(ctx context.Context, setID string) (*SetDetails, error) {
+	setURL := c.endpoint("sets/%s", setID)
+
+	var setDetails SetDetails
+	if err := c.getJSON(ctx, setURL, "set "+setID, &setDetails); err != nil {
+		return nil, err
+	}
+
+	return &setDetails, nil
+}
+
 // FetchSetCards fetches the full details for every card in a given set.
+// See FetchCardsInSet for how the individual cards are fetched.
 func (c *Client) FetchSetCards(setID string) ([]Card, error) {
+	return c.FetchSetCardsContext(context.Background(), setID)
+}
+
+// FetchSetCardsContext is like FetchSetCards but stops early if ctx is done.
+func (c *Client) FetchSetCardsContext(ctx context.Context, setID string) ([]Card, error) {
 	// First, fetch the set to get the list of card IDs
-	setURL := fmt.Sprintf("%s/sets/%s", apiBaseURL, setID)
-	req, err := http.NewRequest("GET", setURL, nil)
+	setDetails, err := c.FetchSetContext(ctx, setID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request for set %s: %w", setID, err)
+		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch set %s: %w", setID, err)
-	}
-	defer resp.Body.Close()
+	return c.FetchCardsInSetContext(ctx, setDetails)
+}
+
+// FetchCardsInSet fetches the full details for every card listed in set.
+// Cards are fetched concurrently, bounded by the client's concurrency and rate
+// limit, and are returned in the order the set lists them. If any card still
+// fails after retrying, the remaining cards are returned together with an
+// *IncompleteSetError describing the failures.
+func (c *Client) FetchCardsInSet(setDetails *SetDetails) ([]Card, error) {
+	return c.FetchCardsInSetContext(context.Background(), setDetails)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad status code when fetching set %s: %d", setID, resp.StatusCode)
+// FetchCardsInSetContext is like FetchCardsInSet but stops early if ctx is
+// done, in which case the cards fetched so far are returned with ctx's error.
+func (c *Client) FetchCardsInSetContext(ctx context.Context, setDetails *SetDetails) ([]Card, error) {
+	// Fetch each card individually to get full details. Each worker writes
+	// into its own slot so the output order matches the set listing.
+	total := len(setDetails.Cards)
+	results := make([]*Card, total)
+	failures := make([]error, total)
+	jobs := make(chan int)
+
+	// Reporting under doneMu keeps events in order of their Done count.
+	var doneMu sync.Mutex
+	done := 0
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(c.concurrency, total); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				cardSummary := setDetails.Cards[i]
+				card, err := c.FetchCardContext(ctx, cardSummary.ID)
+				if ctx.Err() != nil {
+					// Cancelled cards are neither fetched nor failed.
+					continue
+				}
+
+				event := ProgressEvent{
+					Kind:     ProgressCardFetched,
+					SetID:    setDetails.ID,
+					CardID:   cardSummary.ID,
+					CardName: cardSummary.Name,
+					Total:    total,
+				}
+				if err != nil {
+					// Record the error but continue trying to fetch other cards
+					failures[i] = err
+					event.Kind = ProgressCardFailed
+					event.Err = err
+				} else {
+					results[i] = card
+				}
+
+				doneMu.Lock()
+				done++
+				event.Done = done
+				c.report(event)
+				doneMu.Unlock()
+			}
+		}()
 	}
 
-	var setDetails SetDetails
-	if err := json.NewDecoder(resp.Body).Decode(&setDetails); err != nil {
-		return nil, fmt.Errorf("failed to decode set details for %s: %w", setID, err)
+dispatch:
+	for i := range setDetails.Cards {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
 	}
+	close(jobs)
+	wg.Wait()
 
-	var fullCards []Card
-	// Now, fetch each card individually to get full details
-	for _, cardSummary := range setDetails.Cards {
-		fmt.Printf("Fetching card: %s (%s)\n", cardSummary.Name, cardSummary.ID)
-		card, err := c.FetchCard(cardSummary.ID)
-		if err != nil {
-			// Log the error but continue trying to fetch other cards
-			fmt.Printf("Warning: failed to fetch card %s: %v\n", cardSummary.ID, err)
+	fullCards := make([]Card, 0, len(results))
+	var failed []CardError
+	for i, card := range results {
+		if card != nil {
+			fullCards = append(fullCards, *card)
 			continue
 		}
-		// Small delay to be respectful to the API
-		time.Sleep(50 * time.Millisecond)
-		fullCards = append(fullCards, *card)
+		if failures[i] == nil {
+			continue // Never attempted because the fetch was cancelled
+		}
+		cardSummary := setDetails.Cards[i]
+		failed = append(failed, CardError{CardID: cardSummary.ID, Name: cardSummary.Name, Err: failures[i]})
 	}
 
+	if err := ctx.Err(); err != nil {
+		return fullCards, err
+	}
+	if len(failed) > 0 {
+		return fullCards, &IncompleteSetError{SetID: setDetails.ID, Failed: failed}
+	}
 	return fullCards, nil
 }
 
 // FetchCard fetches a single card by its full ID (e.g., "pock-1").
 func (c *Client) FetchCard(cardID string) (*Card, error) {
-	cardURL := fmt.Sprintf("%s/cards/%s", apiBaseURL, cardID)
-	req, err := http.NewRequest("GET", cardURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request for card %s: %w", cardID, err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch card %s: %w", cardID, err)
-	}
-	defer resp.Body.Close()
+	return c.FetchCardContext(context.Background(), cardID)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad status code when fetching card %s: %d", cardID, resp.StatusCode)
-	}
+// FetchCardContext is like FetchCard but stops early if ctx is done.
+func (c *Client) FetchCardContext(ctx context.Context, cardID string) (*Card, error) {
+	cardURL := c.endpoint("cards/%s", cardID)
 
 	var card Card
-	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
-		return nil, fmt.Errorf("failed to decode card %s: %w", cardID, err)
+	if err := c.getJSON(ctx, cardURL, "card "+cardID, &card); err != nil {
+		return nil, err
 	}
 
 	return &card, nil
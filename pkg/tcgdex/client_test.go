@@ -0,0 +1,123 @@
+package tcgdex_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+	"github.com/cpritch/genomon/pkg/tcgdex/tcgdextest"
+)
+
+// fixtureCards loads the first n cards of the checked-in dataset.
+func fixtureCards(t *testing.T, n int) []tcgdex.Card {
+	t.Helper()
+	cards, err := tcgdextest.LoadFixtures("../../ptcgp-cards.json")
+	if err != nil {
+		t.Fatalf("loading fixtures: %v", err)
+	}
+	return cards[:n]
+}
+
+// newTestClient returns a client for server with rate limiting disabled and
+// near-instant retries.
+func newTestClient(server *tcgdextest.Server, opts ...tcgdex.Option) *tcgdex.Client {
+	opts = append([]tcgdex.Option{
+		tcgdex.WithBaseURL(server.BaseURL()),
+		tcgdex.WithRateLimit(0, 0),
+		tcgdex.WithRetryPolicy(tcgdex.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	}, opts...)
+	return tcgdex.NewClient(opts...)
+}
+
+func TestFetchSetCardsPreservesOrder(t *testing.T) {
+	cards := fixtureCards(t, 40)
+	server := tcgdextest.NewServer(cards)
+	defer server.Close()
+
+	client := newTestClient(server, tcgdex.WithConcurrency(8))
+	got, err := client.FetchSetCards("A1")
+	if err != nil {
+		t.Fatalf("FetchSetCards: %v", err)
+	}
+	if !reflect.DeepEqual(got, cards) {
+		t.Errorf("FetchSetCards returned %d cards that differ from the %d served", len(got), len(cards))
+	}
+}
+
+func TestFetchCardRetriesTransientErrors(t *testing.T) {
+	server := tcgdextest.NewServer(fixtureCards(t, 1))
+	defer server.Close()
+	server.FailNext("/v2/en/cards/A1-001", http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	card, err := newTestClient(server).FetchCard("A1-001")
+	if err != nil {
+		t.Fatalf("FetchCard: %v", err)
+	}
+	if card.Name != "Bulbasaur" {
+		t.Errorf("got card %q, want Bulbasaur", card.Name)
+	}
+	if n := server.Requests("/v2/en/cards/A1-001"); n != 3 {
+		t.Errorf("made %d requests, want 3", n)
+	}
+}
+
+func TestFetchSetCardsReportsIncompleteSet(t *testing.T) {
+	server := tcgdextest.NewServer(fixtureCards(t, 3))
+	defer server.Close()
+	server.FailNext("/v2/en/cards/A1-002", http.StatusNotFound)
+
+	got, err := newTestClient(server).FetchSetCards("A1")
+	var incomplete *tcgdex.IncompleteSetError
+	if !errors.As(err, &incomplete) {
+		t.Fatalf("got error %v, want *IncompleteSetError", err)
+	}
+	if len(incomplete.Failed) != 1 || incomplete.Failed[0].CardID != "A1-002" {
+		t.Errorf("failed cards = %+v, want only A1-002", incomplete.Failed)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d cards, want the 2 that succeeded", len(got))
+	}
+	// A 404 is permanent, so it must not be retried.
+	if n := server.Requests("/v2/en/cards/A1-002"); n != 1 {
+		t.Errorf("made %d requests for a missing card, want 1", n)
+	}
+}
+
+func TestCacheRevalidatesResponses(t *testing.T) {
+	server := tcgdextest.NewServer(fixtureCards(t, 1))
+	defer server.Close()
+
+	cache, err := tcgdex.NewCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+	client := newTestClient(server, tcgdex.WithCache(cache))
+	const path = "/v2/en/cards/A1-001"
+	for i := 0; i < 2; i++ {
+		card, err := client.FetchCard("A1-001")
+		if err != nil {
+			t.Fatalf("FetchCard (attempt %d): %v", i+1, err)
+		}
+		if card.Name != "Bulbasaur" {
+			t.Errorf("got card %q from attempt %d, want Bulbasaur", card.Name, i+1)
+		}
+		if i == 0 && server.Revalidations(path) != 0 {
+			t.Errorf("first fetch sent If-None-Match with nothing cached")
+		}
+	}
+
+	// The second fetch must revalidate the cached response, and since a 304
+	// has no body, the card it returned came from the cache.
+	if n := server.Revalidations(path); n != 1 {
+		t.Errorf("sent If-None-Match %d times, want once", n)
+	}
+	if n := server.Responses(path, http.StatusNotModified); n != 1 {
+		t.Errorf("got %d 304 responses, want 1", n)
+	}
+	if n := server.Responses(path, http.StatusOK); n != 1 {
+		t.Errorf("got %d 200 responses, want only the first", n)
+	}
+}
@@ -0,0 +1,126 @@
+package tcgdex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DamageModifier is the suffix printed after an attack's damage, describing
+// how the base value is adjusted by the attack's effect.
+type DamageModifier string
+
+const (
+	DamagePlain DamageModifier = "plain" // "40": exactly the base damage
+	DamagePlus  DamageModifier = "plus"  // "30+": base damage plus a bonus
+	DamageTimes DamageModifier = "times" // "50×": base damage times a count
+	DamageMinus DamageModifier = "minus" // "100-": base damage minus a penalty
+)
+
+// damageSuffixes maps the printed suffixes TCGdex uses to their modifier.
+var damageSuffixes = map[string]DamageModifier{
+	"+": DamagePlus,
+	"×": DamageTimes,
+	"x": DamageTimes,
+	"-": DamageMinus,
+	"−": DamageMinus,
+}
+
+// Damage is an attack's printed damage. TCGdex returns it either as a number
+// or as a string such as "40", "30+" or "50×"; Damage exposes the base value
+// and modifier while remembering the original JSON so it round-trips
+// losslessly.
+type Damage struct {
+	Base     int
+	Modifier DamageModifier
+
+	raw json.RawMessage // Original JSON, reused when marshalling if still accurate
+}
+
+// ParseDamage parses a printed damage value such as "40", "30+" or "50×".
+// An empty value is no damage.
+func ParseDamage(s string) (Damage, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Damage{}, nil
+	}
+	modifier := DamagePlain
+	for suffix, m := range damageSuffixes {
+		if strings.HasSuffix(s, suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, suffix))
+			modifier = m
+			break
+		}
+	}
+
+	base, err := strconv.Atoi(s)
+	if err != nil {
+		return Damage{}, fmt.Errorf("invalid damage %q", s)
+	}
+	return Damage{Base: base, Modifier: modifier}, nil
+}
+
+// IsZero reports whether the attack has no printed damage.
+func (d Damage) IsZero() bool {
+	return d.Base == 0 && (d.Modifier == "" || (d.Modifier == DamagePlain && len(d.raw) == 0))
+}
+
+// String returns the damage as printed on the card, e.g. "30+" or "50×".
+func (d Damage) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	switch d.Modifier {
+	case DamagePlus:
+		return fmt.Sprintf("%d+", d.Base)
+	case DamageTimes:
+		return fmt.Sprintf("%d×", d.Base)
+	case DamageMinus:
+		return fmt.Sprintf("%d-", d.Base)
+	}
+	return strconv.Itoa(d.Base)
+}
+
+// UnmarshalJSON accepts either a JSON number or a printed damage string.
+func (d *Damage) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Damage{raw: json.RawMessage("null")}
+		return nil
+	}
+
+	var parsed Damage
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		parsed = Damage{Base: number, Modifier: DamagePlain}
+	} else {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("damage must be a number or string, got %s", data)
+		}
+		if parsed, err = ParseDamage(text); err != nil {
+			return err
+		}
+	}
+
+	parsed.raw = append(json.RawMessage(nil), data...)
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON writes the original representation if the value hasn't been
+// changed since it was decoded, and otherwise a canonical one: a number for
+// plain damage and a printed string such as "30+" for the rest.
+func (d Damage) MarshalJSON() ([]byte, error) {
+	if len(d.raw) > 0 {
+		var original Damage
+		if err := original.UnmarshalJSON(d.raw); err == nil && original.Base == d.Base && original.Modifier == d.Modifier {
+			return d.raw, nil
+		}
+	}
+	if d.Modifier == "" || d.Modifier == DamagePlain {
+		return json.Marshal(d.Base)
+	}
+	return json.Marshal(d.String())
+}
@@ -0,0 +1,115 @@
+package tcgdex_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+func TestParseDamage(t *testing.T) {
+	tests := []struct {
+		in   string
+		want tcgdex.Damage
+	}{
+		{"40", tcgdex.Damage{Base: 40, Modifier: tcgdex.DamagePlain}},
+		{"30+", tcgdex.Damage{Base: 30, Modifier: tcgdex.DamagePlus}},
+		{"50×", tcgdex.Damage{Base: 50, Modifier: tcgdex.DamageTimes}},
+		{"50x", tcgdex.Damage{Base: 50, Modifier: tcgdex.DamageTimes}},
+		{"100-", tcgdex.Damage{Base: 100, Modifier: tcgdex.DamageMinus}},
+		{" 20 + ", tcgdex.Damage{Base: 20, Modifier: tcgdex.DamagePlus}},
+		{"", tcgdex.Damage{}},
+	}
+	for _, tt := range tests {
+		got, err := tcgdex.ParseDamage(tt.in)
+		if err != nil {
+			t.Errorf("ParseDamage(%q): %v", tt.in, err)
+			continue
+		}
+		if got.Base != tt.want.Base || got.Modifier != tt.want.Modifier {
+			t.Errorf("ParseDamage(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"lots", "+", "30++"} {
+		if got, err := tcgdex.ParseDamage(in); err == nil {
+			t.Errorf("ParseDamage(%q) = %+v, want an error", in, got)
+		}
+	}
+}
+
+func TestDamageJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		json     string
+		base     int
+		modifier tcgdex.DamageModifier
+		printed  string
+	}{
+		{`40`, 40, tcgdex.DamagePlain, "40"},
+		{`"40"`, 40, tcgdex.DamagePlain, "40"},
+		{`"30+"`, 30, tcgdex.DamagePlus, "30+"},
+		{`"50×"`, 50, tcgdex.DamageTimes, "50×"},
+		{`"50x"`, 50, tcgdex.DamageTimes, "50×"},
+		{`"100-"`, 100, tcgdex.DamageMinus, "100-"},
+		{`null`, 0, "", ""},
+		{`""`, 0, "", ""},
+	}
+	for _, tt := range tests {
+		var d tcgdex.Damage
+		if err := json.Unmarshal([]byte(tt.json), &d); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.json, err)
+			continue
+		}
+		if d.Base != tt.base || d.Modifier != tt.modifier || d.String() != tt.printed {
+			t.Errorf("Unmarshal(%s) = %d %q %q, want %d %q %q", tt.json, d.Base, d.Modifier, d, tt.base, tt.modifier, tt.printed)
+		}
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Errorf("Marshal(%s): %v", tt.json, err)
+			continue
+		}
+		if string(data) != tt.json {
+			t.Errorf("Marshal(Unmarshal(%s)) = %s, want it unchanged", tt.json, data)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`"lots"`), new(tcgdex.Damage)); err == nil {
+		t.Error(`Unmarshal("lots") succeeded, want an error`)
+	}
+}
+
+func TestDamageJSONModified(t *testing.T) {
+	tests := []struct {
+		json   string
+		modify func(*tcgdex.Damage)
+		want   string
+	}{
+		{`"50x"`, func(d *tcgdex.Damage) { d.Base = 60 }, `"60×"`},
+		{`"30+"`, func(d *tcgdex.Damage) { d.Modifier = tcgdex.DamagePlain }, `30`},
+		{`"40"`, func(d *tcgdex.Damage) { d.Modifier = tcgdex.DamagePlus }, `"40+"`},
+	}
+	for _, tt := range tests {
+		var d tcgdex.Damage
+		if err := json.Unmarshal([]byte(tt.json), &d); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", tt.json, err)
+		}
+		tt.modify(&d)
+		data, err := json.Marshal(d)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("Marshal after modifying %s = %s, want %s", tt.json, data, tt.want)
+		}
+	}
+}
+
+func TestDamageEmptyStringInCard(t *testing.T) {
+	var card tcgdex.Card
+	if err := json.Unmarshal([]byte(`{"id": "A1-001", "attacks": [{"name": "Growl", "damage": ""}]}`), &card); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if damage := card.Attacks[0].Damage; !damage.IsZero() {
+		t.Errorf("empty damage decoded as %+v, want no damage", damage)
+	}
+}
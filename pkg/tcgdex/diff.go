@@ -0,0 +1,197 @@
+package tcgdex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Changelog describes what changed between two snapshots of card data, such
+// as two versions of ptcgp-cards.json.
+type Changelog struct {
+	AddedSets    []Set        `json:"addedSets,omitempty"`
+	RemovedSets  []Set        `json:"removedSets,omitempty"`
+	AddedCards   []Card       `json:"addedCards,omitempty"`
+	RemovedCards []Card       `json:"removedCards,omitempty"`
+	ChangedCards []CardChange `json:"changedCards,omitempty"`
+}
+
+// CardChange lists the differences found for a card present in both snapshots.
+type CardChange struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange is a single changed field of a card.
+type FieldChange struct {
+	// Field is a path to the changed value, e.g. "hp" or "attacks[1].effect".
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+
+	// EffectText is set when the change is to the text of an attack, ability
+	// or trainer effect, meaning any parsed effects for it need re-review.
+	// Source names the attack or ability (empty for trainer text).
+	EffectText bool   `json:"effectText,omitempty"`
+	Source     string `json:"source,omitempty"`
+}
+
+// Empty reports whether the snapshots were identical in every compared field.
+func (c *Changelog) Empty() bool {
+	return len(c.AddedSets) == 0 && len(c.RemovedSets) == 0 && len(c.AddedCards) == 0 &&
+		len(c.RemovedCards) == 0 && len(c.ChangedCards) == 0
+}
+
+// TextChanges returns the changed cards that have at least one effect text change.
+func (c *Changelog) TextChanges() []CardChange {
+	var changes []CardChange
+	for _, change := range c.ChangedCards {
+		for _, field := range change.Changes {
+			if field.EffectText {
+				changes = append(changes, change)
+				break
+			}
+		}
+	}
+	return changes
+}
+
+// Diff compares two card snapshots and reports added and removed sets and
+// cards, and field-level changes to cards present in both. Results are sorted
+// by ID so the output is stable.
+func Diff(oldCards, newCards []Card) *Changelog {
+	changelog := &Changelog{}
+
+	oldByID := indexCards(oldCards)
+	newByID := indexCards(newCards)
+	oldSets := indexSets(oldCards)
+	newSets := indexSets(newCards)
+
+	for id, set := range newSets {
+		if _, ok := oldSets[id]; !ok {
+			changelog.AddedSets = append(changelog.AddedSets, set)
+		}
+	}
+	for id, set := range oldSets {
+		if _, ok := newSets[id]; !ok {
+			changelog.RemovedSets = append(changelog.RemovedSets, set)
+		}
+	}
+
+	for id, card := range newByID {
+		oldCard, ok := oldByID[id]
+		if !ok {
+			changelog.AddedCards = append(changelog.AddedCards, card)
+			continue
+		}
+		if changes := diffCard(oldCard, card); len(changes) > 0 {
+			changelog.ChangedCards = append(changelog.ChangedCards, CardChange{ID: id, Name: card.Name, Changes: changes})
+		}
+	}
+	for id, card := range oldByID {
+		if _, ok := newByID[id]; !ok {
+			changelog.RemovedCards = append(changelog.RemovedCards, card)
+		}
+	}
+
+	sort.Slice(changelog.AddedSets, func(i, j int) bool { return changelog.AddedSets[i].ID < changelog.AddedSets[j].ID })
+	sort.Slice(changelog.RemovedSets, func(i, j int) bool { return changelog.RemovedSets[i].ID < changelog.RemovedSets[j].ID })
+	sort.Slice(changelog.AddedCards, func(i, j int) bool { return changelog.AddedCards[i].ID < changelog.AddedCards[j].ID })
+	sort.Slice(changelog.RemovedCards, func(i, j int) bool { return changelog.RemovedCards[i].ID < changelog.RemovedCards[j].ID })
+	sort.Slice(changelog.ChangedCards, func(i, j int) bool { return changelog.ChangedCards[i].ID < changelog.ChangedCards[j].ID })
+
+	return changelog
+}
+
+func indexCards(cards []Card) map[string]Card {
+	byID := make(map[string]Card, len(cards))
+	for _, card := range cards {
+		byID[card.ID] = card
+	}
+	return byID
+}
+
+func indexSets(cards []Card) map[string]Set {
+	byID := make(map[string]Set)
+	for _, card := range cards {
+		byID[card.Set.ID] = card.Set
+	}
+	return byID
+}
+
+// diffCard compares the gameplay-relevant fields of two versions of a card.
+func diffCard(oldCard, newCard Card) []FieldChange {
+	var changes []FieldChange
+	compare := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	compareText := func(field, source, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue, EffectText: true, Source: source})
+		}
+	}
+
+	compare("name", oldCard.Name, newCard.Name)
+	compare("category", oldCard.Category, newCard.Category)
+	compare("rarity", oldCard.Rarity, newCard.Rarity)
+	compare("hp", fmt.Sprint(oldCard.HP), fmt.Sprint(newCard.HP))
+	compare("types", strings.Join(oldCard.Types, ", "), strings.Join(newCard.Types, ", "))
+	compare("stage", oldCard.Stage, newCard.Stage)
+	compare("evolveFrom", oldCard.EvolveFrom, newCard.EvolveFrom)
+	compare("retreat", fmt.Sprint(oldCard.Retreat), fmt.Sprint(newCard.Retreat))
+	compare("weaknesses", formatWeaknesses(oldCard.Weaknesses), formatWeaknesses(newCard.Weaknesses))
+	compareText("effect", "", oldCard.Text, newCard.Text)
+
+	for i := 0; i < max(len(oldCard.Attacks), len(newCard.Attacks)); i++ {
+		field := fmt.Sprintf("attacks[%d]", i)
+		if i >= len(oldCard.Attacks) {
+			compareText(field, newCard.Attacks[i].Name, "", formatAttack(newCard.Attacks[i]))
+			continue
+		}
+		if i >= len(newCard.Attacks) {
+			compareText(field, oldCard.Attacks[i].Name, formatAttack(oldCard.Attacks[i]), "")
+			continue
+		}
+		oldAttack, newAttack := oldCard.Attacks[i], newCard.Attacks[i]
+		compare(field+".name", oldAttack.Name, newAttack.Name)
+		compare(field+".cost", strings.Join(oldAttack.Cost, ", "), strings.Join(newAttack.Cost, ", "))
+		compare(field+".damage", oldAttack.Damage.String(), newAttack.Damage.String())
+		compareText(field+".effect", newAttack.Name, oldAttack.Effect, newAttack.Effect)
+	}
+
+	for i := 0; i < max(len(oldCard.Abilities), len(newCard.Abilities)); i++ {
+		field := fmt.Sprintf("abilities[%d]", i)
+		if i >= len(oldCard.Abilities) {
+			compareText(field, newCard.Abilities[i].Name, "", newCard.Abilities[i].Name+": "+newCard.Abilities[i].Effect)
+			continue
+		}
+		if i >= len(newCard.Abilities) {
+			compareText(field, oldCard.Abilities[i].Name, oldCard.Abilities[i].Name+": "+oldCard.Abilities[i].Effect, "")
+			continue
+		}
+		oldAbility, newAbility := oldCard.Abilities[i], newCard.Abilities[i]
+		compare(field+".name", oldAbility.Name, newAbility.Name)
+		compareText(field+".effect", newAbility.Name, oldAbility.Effect, newAbility.Effect)
+	}
+
+	return changes
+}
+
+func formatWeaknesses(weaknesses []Weakness) string {
+	parts := make([]string, len(weaknesses))
+	for i, weakness := range weaknesses {
+		parts[i] = weakness.Type + " " + weakness.Value
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatAttack(attack Attack) string {
+	text := fmt.Sprintf("%s [%s] %s", attack.Name, strings.Join(attack.Cost, ", "), attack.Damage)
+	if attack.Effect != "" {
+		text += ": " + attack.Effect
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,77 @@
+package tcgdex_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+func TestDiffAddedAndRemoved(t *testing.T) {
+	a1 := tcgdex.Set{ID: "A1", Name: "Genetic Apex"}
+	a2 := tcgdex.Set{ID: "A2", Name: "Space-Time Smackdown"}
+	bulbasaur := tcgdex.Card{ID: "A1-001", Name: "Bulbasaur", Set: a1}
+	ivysaur := tcgdex.Card{ID: "A1-002", Name: "Ivysaur", Set: a1}
+	oddish := tcgdex.Card{ID: "A2-001", Name: "Oddish", Set: a2}
+
+	changelog := tcgdex.Diff([]tcgdex.Card{bulbasaur, ivysaur}, []tcgdex.Card{oddish, bulbasaur})
+	if !reflect.DeepEqual(changelog.AddedSets, []tcgdex.Set{a2}) || len(changelog.RemovedSets) != 0 {
+		t.Errorf("sets added %+v, removed %+v, want A2 added", changelog.AddedSets, changelog.RemovedSets)
+	}
+	if len(changelog.AddedCards) != 1 || changelog.AddedCards[0].ID != "A2-001" {
+		t.Errorf("added cards = %+v, want A2-001", changelog.AddedCards)
+	}
+	if len(changelog.RemovedCards) != 1 || changelog.RemovedCards[0].ID != "A1-002" {
+		t.Errorf("removed cards = %+v, want A1-002", changelog.RemovedCards)
+	}
+	if len(changelog.ChangedCards) != 0 {
+		t.Errorf("changed cards = %+v, want none", changelog.ChangedCards)
+	}
+
+	if changelog := tcgdex.Diff([]tcgdex.Card{bulbasaur}, []tcgdex.Card{bulbasaur}); !changelog.Empty() {
+		t.Errorf("Diff of identical snapshots = %+v, want empty", changelog)
+	}
+}
+
+func TestDiffTextChanges(t *testing.T) {
+	old := tcgdex.Card{
+		ID: "A1-047", Name: "Moltres ex", Category: "Pokemon", HP: 140,
+		Attacks:   []tcgdex.Attack{{Name: "Inferno Dance", Cost: []string{"Fire"}, Effect: "Flip 3 coins."}},
+		Abilities: []tcgdex.Ability{{Name: "Blaze", Effect: "Once during your turn, you may heal 10 damage."}},
+	}
+	errata := old
+	errata.HP = 150
+	errata.Attacks = []tcgdex.Attack{{Name: "Inferno Dance", Cost: []string{"Fire"}, Effect: "Flip 4 coins."}}
+	errata.Abilities = []tcgdex.Ability{{Name: "Blaze", Effect: "Once during your turn, you may heal 20 damage."}}
+
+	changelog := tcgdex.Diff([]tcgdex.Card{old}, []tcgdex.Card{errata})
+	if len(changelog.ChangedCards) != 1 {
+		t.Fatalf("changed cards = %+v, want 1", changelog.ChangedCards)
+	}
+	want := []tcgdex.FieldChange{
+		{Field: "hp", Old: "140", New: "150"},
+		{Field: "attacks[0].effect", Old: "Flip 3 coins.", New: "Flip 4 coins.", EffectText: true, Source: "Inferno Dance"},
+		{Field: "abilities[0].effect", Old: "Once during your turn, you may heal 10 damage.", New: "Once during your turn, you may heal 20 damage.", EffectText: true, Source: "Blaze"},
+	}
+	if got := changelog.ChangedCards[0].Changes; !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %+v\nwant %+v", got, want)
+	}
+	if got := changelog.TextChanges(); len(got) != 1 || got[0].ID != "A1-047" {
+		t.Errorf("TextChanges() = %+v, want A1-047", got)
+	}
+
+	// Trainer text is effect text with no attack or ability to name.
+	potion := tcgdex.Card{ID: "P-A-001", Name: "Potion", Category: "Trainer", Text: "Heal 20 damage from 1 of your Pokémon."}
+	potionErrata := potion
+	potionErrata.Text = "Heal 30 damage from 1 of your Pokémon."
+	changes := tcgdex.Diff([]tcgdex.Card{potion}, []tcgdex.Card{potionErrata}).ChangedCards[0].Changes
+	if len(changes) != 1 || changes[0].Field != "effect" || !changes[0].EffectText || changes[0].Source != "" {
+		t.Errorf("Trainer text change = %+v", changes)
+	}
+
+	// A stat change alone isn't a text change.
+	if got := tcgdex.Diff([]tcgdex.Card{old}, []tcgdex.Card{{ID: old.ID, Name: old.Name, Category: old.Category, HP: 150,
+		Attacks: old.Attacks, Abilities: old.Abilities}}).TextChanges(); len(got) != 0 {
+		t.Errorf("TextChanges() for an HP change = %+v, want none", got)
+	}
+}
@@ -0,0 +1,90 @@
+package tcgdex
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ImageQuality selects the resolution of a card image.
+type ImageQuality string
+
+const (
+	ImageLow  ImageQuality = "low"  // Roughly 245x337
+	ImageHigh ImageQuality = "high" // Roughly 600x825
+)
+
+// ImageFormat selects the file format of a card image.
+type ImageFormat string
+
+const (
+	ImagePNG  ImageFormat = "png"
+	ImageWebP ImageFormat = "webp"
+)
+
+// ParseImageQuality validates an image quality name such as "low" or "high".
+func ParseImageQuality(s string) (ImageQuality, error) {
+	switch q := ImageQuality(s); q {
+	case ImageLow, ImageHigh:
+		return q, nil
+	}
+	return "", fmt.Errorf("unknown image quality %q (want low or high)", s)
+}
+
+// ParseImageFormat validates an image format name such as "png" or "webp".
+func ParseImageFormat(s string) (ImageFormat, error) {
+	switch f := ImageFormat(s); f {
+	case ImagePNG, ImageWebP:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown image format %q (want png or webp)", s)
+}
+
+// ImageURL returns the URL of the card's artwork in the given quality and
+// format, or "" if TCGdex has no image for the card.
+func (c *Card) ImageURL(quality ImageQuality, format ImageFormat) string {
+	if c.Image == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s.%s", c.Image, quality, format)
+}
+
+// Image is the result of fetching a card image.
+type Image struct {
+	Data         []byte // Empty when NotModified is set
+	ETag         string
+	LastModified string
+	NotModified  bool // The image is unchanged since the validators passed to FetchImage
+}
+
+// FetchImage downloads the image at url. If etag or lastModified are given,
+// the request is conditional and an unchanged image comes back with
+// NotModified set instead of its data. Images bypass the response cache.
+func (c *Client) FetchImage(url, etag, lastModified string) (*Image, error) {
+	return c.FetchImageContext(context.Background(), url, etag, lastModified)
+}
+
+// FetchImageContext is like FetchImage but aborts when ctx is cancelled.
+func (c *Client) FetchImageContext(ctx context.Context, url, etag, lastModified string) (*Image, error) {
+	header := http.Header{}
+	setConditionalHeaders(header, etag, lastModified)
+
+	resp, err := c.get(ctx, url, "image "+url, header)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &Image{
+		Data:         resp.Body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		NotModified:  resp.StatusCode == http.StatusNotModified,
+	}
+	if image.NotModified {
+		// A 304 may omit the validators; the caller's are still current.
+		image.ETag = cmp.Or(image.ETag, etag)
+		image.LastModified = cmp.Or(image.LastModified, lastModified)
+	}
+	return image, nil
+}
@@ -0,0 +1,66 @@
+package tcgdex
+
+// LocalizedCard holds the translated text of a card in a single language.
+// Attacks and Abilities line up by index with the canonical (English) card.
+type LocalizedCard struct {
+	Name      string          `json:"name"`
+	Effect    string          `json:"effect,omitempty"` // For Trainer/Item cards
+	Attacks   []LocalizedText `json:"attacks,omitempty"`
+	Abilities []LocalizedText `json:"abilities,omitempty"`
+}
+
+// LocalizedText is the translated name and effect text of an attack or ability.
+type LocalizedText struct {
+	Name   string `json:"name"`
+	Effect string `json:"effect,omitempty"`
+}
+
+// AddLocalization records the text of translated, the same card fetched in
+// another language, under c.Localized[language]. The canonical fields of c are
+// left untouched so effect parsing keeps operating on the English text.
+func (c *Card) AddLocalization(language string, translated *Card) {
+	localized := LocalizedCard{
+		Name:   translated.Name,
+		Effect: translated.Text,
+	}
+	for _, attack := range translated.Attacks {
+		localized.Attacks = append(localized.Attacks, LocalizedText{Name: attack.Name, Effect: attack.Effect})
+	}
+	for _, ability := range translated.Abilities {
+		localized.Abilities = append(localized.Abilities, LocalizedText{Name: ability.Name, Effect: ability.Effect})
+	}
+
+	if c.Localized == nil {
+		c.Localized = make(map[string]LocalizedCard)
+	}
+	c.Localized[language] = localized
+}
+
+// Translation returns the card's text in language, falling back to the
+// canonical English text for anything that hasn't been translated.
+func (c *Card) Translation(language string) LocalizedCard {
+	localized, ok := c.Localized[language]
+
+	result := LocalizedCard{Name: c.Name, Effect: c.Text}
+	if ok && localized.Name != "" {
+		result.Name = localized.Name
+	}
+	if ok && localized.Effect != "" {
+		result.Effect = localized.Effect
+	}
+	for i, attack := range c.Attacks {
+		text := LocalizedText{Name: attack.Name, Effect: attack.Effect}
+		if ok && i < len(localized.Attacks) {
+			text = localized.Attacks[i]
+		}
+		result.Attacks = append(result.Attacks, text)
+	}
+	for i, ability := range c.Abilities {
+		text := LocalizedText{Name: ability.Name, Effect: ability.Effect}
+		if ok && i < len(localized.Abilities) {
+			text = localized.Abilities[i]
+		}
+		result.Abilities = append(result.Abilities, text)
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package tcgdex
+
+import "time"
+
+// ProgressKind identifies what happened in a ProgressEvent.
+type ProgressKind int
+
+const (
+	// ProgressCardFetched is reported after each card in a set is fetched.
+	ProgressCardFetched ProgressKind = iota
+	// ProgressCardFailed is reported when a card still fails after retrying.
+	ProgressCardFailed
+	// ProgressRetrying is reported before a failed request is retried.
+	ProgressRetrying
+)
+
+// ProgressEvent describes a step of a fetch, so callers can render progress
+// however they like instead of the client printing it.
+type ProgressEvent struct {
+	Kind ProgressKind
+
+	// Set and card the event relates to. For card events, Done counts the
+	// cards of the set finished so far (fetched or failed) out of Total.
+	SetID    string
+	CardID   string
+	CardName string
+	Done     int
+	Total    int
+
+	// Retry details, for ProgressRetrying events. Resource describes what is
+	// being fetched, e.g. "card A1-001".
+	Resource string
+	Attempt  int
+	Delay    time.Duration
+
+	// Err is the failure behind a ProgressCardFailed or ProgressRetrying event.
+	Err error
+}
+
+// ProgressFunc receives progress events from a Client. Calls are serialised,
+// so implementations don't need to be safe for concurrent use.
+type ProgressFunc func(ProgressEvent)
+
+// WithProgress reports fetch progress to fn.
+func WithProgress(fn ProgressFunc) Option {
+	return func(c *Client) {
+		c.progress = fn
+	}
+}
+
+// report delivers an event to the client's progress function, if any.
+func (c *Client) report(event ProgressEvent) {
+	if c.progress == nil {
+		return
+	}
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	c.progress(event)
+}
@@ -0,0 +1,59 @@
+package tcgdex
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	tests := []struct {
+		rate     float64
+		burst    int
+		requests int
+		min      time.Duration // Time the requests must take at the least
+	}{
+		{rate: 100, burst: 1, requests: 1, min: 0},
+		{rate: 100, burst: 1, requests: 11, min: 100 * time.Millisecond},
+		{rate: 100, burst: 5, requests: 5, min: 0},
+		{rate: 100, burst: 5, requests: 15, min: 100 * time.Millisecond},
+		{rate: 50, burst: 0, requests: 6, min: 100 * time.Millisecond}, // Burst defaults to 1
+	}
+	for _, tt := range tests {
+		limiter := newRateLimiter(tt.rate, tt.burst)
+		start := time.Now()
+		for i := 0; i < tt.requests; i++ {
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Fatalf("Wait: %v", err)
+			}
+		}
+		elapsed := time.Since(start)
+		// Allow generous slack above the minimum for a loaded machine.
+		if elapsed < tt.min || elapsed > tt.min+500*time.Millisecond {
+			t.Errorf("%d requests at %v/s with burst %d took %v, want about %v", tt.requests, tt.rate, tt.burst, elapsed, tt.min)
+		}
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	limiter := newRateLimiter(0, 10)
+	if limiter != nil {
+		t.Fatalf("newRateLimiter(0, 10) = %+v, want nil", limiter)
+	}
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("Wait on a nil limiter: %v", err)
+	}
+}
+
+func TestRateLimiterCancelled(t *testing.T) {
+	limiter := newRateLimiter(0.1, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+	// The next token is ten seconds away, so Wait must give up with ctx.
+	if err := limiter.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
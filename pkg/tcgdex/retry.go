@@ -0,0 +1,135 @@
+package tcgdex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are retried.
+// Delays grow exponentially from BaseDelay and are capped at MaxDelay, with full
+// jitter applied so concurrent workers don't retry in lockstep.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. Values
+	// below 1 are treated as 1 (no retries).
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy is used by clients unless overridden with WithRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// backoff returns how long to wait before the next attempt, given the number of
+// attempts made so far and any delay requested by the server via Retry-After.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, p.MaxDelay)
+	}
+
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// StatusError is returned when the API responds with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is the delay requested by the server, if any.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Temporary reports whether the request is worth retrying.
+func (e *StatusError) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isRetryable reports whether err is transient. Transport errors are retried
+// unless the request was cancelled; HTTP errors only for rate limiting and
+// server-side failures.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Temporary()
+	}
+	var permanentErr *permanentError
+	return !errors.As(err, &permanentErr)
+}
+
+// permanentError marks a failure that retrying won't fix, such as a malformed request.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// parseRetryAfter parses a Retry-After header, which may be either a number of
+// seconds or an HTTP date. It returns zero if the header is absent or invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if d := when.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// CardError records a card that could not be fetched, even after retrying.
+type CardError struct {
+	CardID string
+	Name   string
+	Err    error
+}
+
+func (e *CardError) Error() string {
+	return fmt.Sprintf("card %s (%s): %v", e.CardID, e.Name, e.Err)
+}
+
+func (e *CardError) Unwrap() error { return e.Err }
+
+// IncompleteSetError is returned by FetchSetCards when one or more cards in a
+// set could not be fetched. The cards that were fetched are still returned
+// alongside it.
+type IncompleteSetError struct {
+	SetID  string
+	Failed []CardError
+}
+
+func (e *IncompleteSetError) Error() string {
+	return fmt.Sprintf("set %s is incomplete: %d card(s) failed to fetch", e.SetID, len(e.Failed))
+}
@@ -0,0 +1,61 @@
+package tcgdex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"5", 5 * time.Second},
+		{"120", 2 * time.Minute},
+		{"-3", 0},
+		{"soon", 0},
+		{"Sat, 01 Mar 2025 12:00:30 GMT", 30 * time.Second},
+		{"Saturday, 01-Mar-25 12:01:00 GMT", time.Minute},
+		{"Sat Mar  1 12:00:10 2025", 10 * time.Second},
+		{"Sat, 01 Mar 2025 11:59:00 GMT", 0}, // Already passed
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	tests := []struct {
+		attempt    int
+		retryAfter time.Duration
+		max        time.Duration // Jittered delays fall in [0, max]
+		exact      bool          // The delay is exactly max, without jitter
+	}{
+		{attempt: 1, max: 100 * time.Millisecond},
+		{attempt: 2, max: 200 * time.Millisecond},
+		{attempt: 4, max: 800 * time.Millisecond},
+		{attempt: 5, max: time.Second},   // Capped at MaxDelay
+		{attempt: 100, max: time.Second}, // The shift overflows
+		{attempt: 1, retryAfter: 300 * time.Millisecond, max: 300 * time.Millisecond, exact: true},
+		{attempt: 1, retryAfter: time.Minute, max: time.Second, exact: true},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			got := policy.backoff(tt.attempt, tt.retryAfter)
+			if got < 0 || got > tt.max || tt.exact && got != tt.max {
+				t.Errorf("backoff(%d, %v) = %v, want at most %v (exact: %v)", tt.attempt, tt.retryAfter, got, tt.max, tt.exact)
+				break
+			}
+		}
+	}
+
+	if got := (RetryPolicy{}).backoff(3, 0); got != 0 {
+		t.Errorf("backoff with no delays = %v, want 0", got)
+	}
+}
@@ -0,0 +1,65 @@
+package tcgdex
+
+// SetInfo is the set metadata the sync command writes to sets.json, combining
+// the set endpoint's details with the booster membership of each card.
+type SetInfo struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	ReleaseDate string        `json:"releaseDate,omitempty"`
+	CardCount   CardCount     `json:"cardCount"`
+	Legal       Legal         `json:"legal"`
+	Boosters    []BoosterInfo `json:"boosters,omitempty"`
+
+	// CardIDs lists every card in the set, in set order.
+	CardIDs []string `json:"cardIds"`
+	// UnassignedCardIDs lists cards that don't name any booster, such as
+	// promos that can't be pulled from packs.
+	UnassignedCardIDs []string `json:"unassignedCardIds,omitempty"`
+}
+
+// BoosterInfo is a booster pack along with the cards that can be pulled from it.
+type BoosterInfo struct {
+	Booster
+	CardIDs []string `json:"cardIds"`
+}
+
+// NewSetInfo builds the metadata for set from its details and fetched cards.
+// Boosters are listed in the order the set gives them, followed by any that
+// only appear on cards.
+func NewSetInfo(set *SetDetails, cards []Card) SetInfo {
+	info := SetInfo{
+		ID:          set.ID,
+		Name:        set.Name,
+		ReleaseDate: set.ReleaseDate,
+		CardCount:   set.CardCount,
+		Legal:       set.Legal,
+	}
+
+	boosterIndex := make(map[string]int)
+	addBooster := func(booster Booster) int {
+		i, ok := boosterIndex[booster.ID]
+		if !ok {
+			i = len(info.Boosters)
+			boosterIndex[booster.ID] = i
+			info.Boosters = append(info.Boosters, BoosterInfo{Booster: booster, CardIDs: []string{}})
+		}
+		return i
+	}
+	for _, booster := range set.Boosters {
+		addBooster(booster)
+	}
+
+	for _, card := range cards {
+		info.CardIDs = append(info.CardIDs, card.ID)
+		if len(card.Boosters) == 0 {
+			info.UnassignedCardIDs = append(info.UnassignedCardIDs, card.ID)
+			continue
+		}
+		for _, booster := range card.Boosters {
+			i := addBooster(booster)
+			info.Boosters[i].CardIDs = append(info.Boosters[i].CardIDs, card.ID)
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,272 @@
+// Package tcgdextest provides a fake TCGdex API server for testing code that
+// uses the tcgdex client without touching the network.
+package tcgdextest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/cpritch/genomon/pkg/tcgdex"
+)
+
+// Server is a fake TCGdex API backed by an in-memory card pool. It serves the
+// series, set and card endpoints used by tcgdex.Client, plus card images under
+// /assets/, with ETag support so response caching can be exercised too.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	cards         map[string]map[string]tcgdex.Card // language -> card ID -> card
+	setOrder      []string
+	sets          map[string]*tcgdex.SetDetails
+	images        map[string][]byte      // request path -> image data
+	failures      map[string][]int       // request path -> status codes to return before succeeding
+	requests      map[string]int         // request path -> number of requests received
+	statuses      map[string]map[int]int // request path -> status code -> number of responses
+	revalidations map[string]int         // request path -> number of conditional requests received
+}
+
+// NewServer starts a fake server serving cards in English. Sets are derived
+// from the cards' Set fields, in the order they first appear, along with the
+// boosters the cards list. The caller must
+// call Close when finished.
+func NewServer(cards []tcgdex.Card) *Server {
+	s := &Server{
+		cards:         make(map[string]map[string]tcgdex.Card),
+		sets:          make(map[string]*tcgdex.SetDetails),
+		images:        make(map[string][]byte),
+		failures:      make(map[string][]int),
+		requests:      make(map[string]int),
+		statuses:      make(map[string]map[int]int),
+		revalidations: make(map[string]int),
+	}
+	s.AddCards(tcgdex.DefaultLanguage, cards)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/{lang}/series/{id}", s.handleSeries)
+	mux.HandleFunc("GET /v2/{lang}/sets/{id}", s.handleSet)
+	mux.HandleFunc("GET /v2/{lang}/cards/{id}", s.handleCard)
+	mux.HandleFunc("GET /assets/", s.handleImage)
+	s.Server = httptest.NewServer(s.countRequests(mux))
+	return s
+}
+
+// LoadFixtures reads cards from a file in the format written by `genomon sync`
+// (such as ptcgp-cards.json) for use with NewServer.
+func LoadFixtures(path string) ([]tcgdex.Card, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cards []tcgdex.Card
+	if err := json.Unmarshal(data, &cards); err != nil {
+		return nil, fmt.Errorf("failed to decode fixtures %s: %w", path, err)
+	}
+	return cards, nil
+}
+
+// BaseURL returns the URL to pass to tcgdex.WithBaseURL.
+func (s *Server) BaseURL() string {
+	return s.URL + "/v2"
+}
+
+// AddCards serves cards in the given language. Cards in languages other than
+// English must also exist in English to be listed in a set.
+func (s *Server) AddCards(language string, cards []tcgdex.Card) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cards[language] == nil {
+		s.cards[language] = make(map[string]tcgdex.Card)
+	}
+	for _, card := range cards {
+		s.cards[language][card.ID] = card
+		if language != tcgdex.DefaultLanguage {
+			continue
+		}
+
+		set, ok := s.sets[card.Set.ID]
+		if !ok {
+			set = &tcgdex.SetDetails{ID: card.Set.ID, Name: card.Set.Name}
+			s.sets[card.Set.ID] = set
+			s.setOrder = append(s.setOrder, card.Set.ID)
+		}
+		set.Cards = append(set.Cards, tcgdex.CardStump{ID: card.ID, LocalID: card.LocalID, Name: card.Name})
+		set.CardCount.Total++
+		for _, booster := range card.Boosters {
+			if !slices.Contains(set.Boosters, booster) {
+				set.Boosters = append(set.Boosters, booster)
+			}
+		}
+	}
+}
+
+// AddImage serves data as an image at path, which must start with "/assets/".
+// Adding an image at the same path again replaces it.
+func (s *Server) AddImage(path string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.images[path] = data
+}
+
+// FailNext makes the next len(statuses) requests for path (e.g.
+// "/v2/en/cards/A1-001") respond with the given status codes, in order.
+func (s *Server) FailNext(path string, statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[path] = append(s.failures[path], statuses...)
+}
+
+// Requests returns how many requests have been made for path.
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// Responses returns how many responses with the given status code have been
+// sent for path.
+func (s *Server) Responses(path string, status int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statuses[path][status]
+}
+
+// Revalidations returns how many conditional requests, carrying an
+// If-None-Match header, have been made for path.
+func (s *Server) Revalidations(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.revalidations[path]
+}
+
+// statusRecorder remembers the status code written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// countRequests records every request and the status of its response, and
+// applies any failures queued with FailNext.
+func (s *Server) countRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		w = recorder
+		defer func() {
+			s.mu.Lock()
+			if s.statuses[r.URL.Path] == nil {
+				s.statuses[r.URL.Path] = make(map[int]int)
+			}
+			s.statuses[r.URL.Path][recorder.status]++
+			s.mu.Unlock()
+		}()
+
+		s.mu.Lock()
+		s.requests[r.URL.Path]++
+		if r.Header.Get("If-None-Match") != "" {
+			s.revalidations[r.URL.Path]++
+		}
+		var status int
+		if queued := s.failures[r.URL.Path]; len(queued) > 0 {
+			status, s.failures[r.URL.Path] = queued[0], queued[1:]
+		}
+		s.mu.Unlock()
+
+		if status != 0 {
+			if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+				w.Header().Set("Retry-After", "0")
+			}
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleSeries(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("id") != tcgdex.PocketSeries {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	series := tcgdex.SeriesDetails{ID: tcgdex.PocketSeries, Name: "Pokémon TCG Pocket"}
+	for _, id := range s.setOrder {
+		series.Sets = append(series.Sets, tcgdex.SetSummary{ID: id, Name: s.sets[id].Name})
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, r, series)
+}
+
+func (s *Server) handleSet(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	set, ok := s.sets[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, r, set)
+}
+
+func (s *Server) handleCard(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	card, ok := s.cards[r.PathValue("lang")][r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, r, card)
+}
+
+func (s *Server) handleImage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data, ok := s.images[r.URL.Path]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeBody(w, r, "image/"+strings.TrimPrefix(path.Ext(r.URL.Path), "."), data)
+}
+
+// writeJSON writes v as the response body.
+func writeJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeBody(w, r, "application/json", body)
+}
+
+// writeBody writes body with a content-derived ETag, answering conditional
+// requests for unchanged content with 304 Not Modified.
+func writeBody(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
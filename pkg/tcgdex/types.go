@@ -17,15 +17,23 @@ type Card struct {
 	Variants       *Variants  `json:"variants,omitempty"`
 	HP             int        `json:"hp,omitempty"`
 	Types          []string   `json:"types,omitempty"`
+	DexID          []int      `json:"dexId,omitempty"` // National Pokédex numbers
 	EvolveFrom     string     `json:"evolveFrom,omitempty"`
 	Stage          string     `json:"stage,omitempty"`
+	Suffix         string     `json:"suffix,omitempty"`      // e.g. "EX" for Pokémon ex
+	TrainerType    string     `json:"trainerType,omitempty"` // Item, Supporter or Tool
 	Attacks        []Attack   `json:"attacks,omitempty"`
 	Abilities      []Ability  `json:"abilities,omitempty"`
 	Weaknesses     []Weakness `json:"weaknesses,omitempty"`
 	Retreat        int        `json:"retreat,omitempty"`
 	RegulationMark string     `json:"regulationMark,omitempty"`
 	Legal          Legal      `json:"legal"`
-	Text           string     `json:"effect,omitempty"` // For Trainer/Item cards
+	Text           string     `json:"effect,omitempty"`   // For Trainer/Item cards
+	Boosters       []Booster  `json:"boosters,omitempty"` // Packs the card can be pulled from
+
+	// Localized holds translated text keyed by language code (e.g. "fr"). It
+	// is filled in by the sync command rather than returned by the API.
+	Localized map[string]LocalizedCard `json:"localized,omitempty"`
 }
 
 // Set contains basic information about the set a card belongs to.
@@ -46,10 +54,10 @@ type Variants struct {
 
 // Attack defines an attack, including its cost, name, effect text, and damage.
 type Attack struct {
-	Cost   []string    `json:"cost"`
-	Name   string      `json:"name"`
-	Effect string      `json:"effect,omitempty"`
-	Damage interface{} `json:"damage,omitempty"` // Can be int or string like "30+"
+	Cost   []string `json:"cost"`
+	Name   string   `json:"name"`
+	Effect string   `json:"effect,omitempty"`
+	Damage Damage   `json:"damage,omitzero"` // Printed as an int or a string like "30+"
 }
 
 // Ability defines a Pokémon's ability.
@@ -73,6 +81,8 @@ type Legal struct {
 
 // SeriesDetails is used to decode the response from the /series/{id} endpoint.
 type SeriesDetails struct {
+	ID   string       `json:"id"`
+	Name string       `json:"name"`
 	Sets []SetSummary `json:"sets"`
 }
 
@@ -84,9 +94,28 @@ type SetSummary struct {
 
 // SetDetails represents the full details of a set, including a list of all its cards.
 type SetDetails struct {
-	ID    string      `json:"id"`
-	Name  string      `json:"name"`
-	Cards []CardStump `json:"cards"`
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Logo        string      `json:"logo,omitempty"`
+	Symbol      string      `json:"symbol,omitempty"`
+	ReleaseDate string      `json:"releaseDate,omitempty"` // YYYY-MM-DD
+	CardCount   CardCount   `json:"cardCount"`
+	Boosters    []Booster   `json:"boosters,omitempty"`
+	Legal       Legal       `json:"legal"`
+	Cards       []CardStump `json:"cards"`
+}
+
+// CardCount holds the number of cards in a set. Official excludes secret
+// rares and other cards numbered beyond the printed set size.
+type CardCount struct {
+	Total    int `json:"total"`
+	Official int `json:"official"`
+}
+
+// Booster identifies a booster pack that cards can be pulled from.
+type Booster struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 // CardStump is a smaller Card struct used for decoding the list of cards in a set response.